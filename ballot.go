@@ -0,0 +1,115 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import "fmt"
+
+// Ballot builds a ballot for VotingsService.Vote method. Choices are ranked in
+// the order in which they are added, starting from rank 1 as the most
+// preferred. Choices that are not added to the Ballot are left unranked and
+// are less preferred than any ranked choice.
+//
+// Errors from building the Ballot, such as ranking the same choice twice, are
+// returned by the Validate method.
+type Ballot struct {
+	ranks map[string]int
+	order []string
+	last  int
+	err   error
+}
+
+// NewBallot returns a new empty Ballot.
+func NewBallot() *Ballot {
+	return &Ballot{
+		ranks: make(map[string]int),
+	}
+}
+
+// Rank ranks choices one after another, each with a lower preference than the
+// previous one and all with lower preference than already ranked choices.
+func (b *Ballot) Rank(choices ...string) *Ballot {
+	for _, c := range choices {
+		b.set(c, b.last+1)
+	}
+	return b
+}
+
+// Tie ranks all choices with the same preference, lower than already ranked
+// choices.
+func (b *Ballot) Tie(choices ...string) *Ballot {
+	rank := b.last + 1
+	for _, c := range choices {
+		b.set(c, rank)
+	}
+	return b
+}
+
+// Set ranks a choice with an explicit rank which must be a positive number.
+// Subsequent Rank and Tie calls rank choices after the highest rank set.
+func (b *Ballot) Set(choice string, rank int) *Ballot {
+	b.set(choice, rank)
+	return b
+}
+
+func (b *Ballot) set(choice string, rank int) {
+	if b.err != nil {
+		return
+	}
+	if choice == "" {
+		b.err = ErrChoiceRequired
+		return
+	}
+	if rank <= 0 {
+		b.err = fmt.Errorf("%w: invalid rank %v for choice %q", ErrInvalidData, rank, choice)
+		return
+	}
+	if _, ok := b.ranks[choice]; ok {
+		b.err = fmt.Errorf("%w: duplicate choice %q", ErrInvalidData, choice)
+		return
+	}
+	b.ranks[choice] = rank
+	b.order = append(b.order, choice)
+	if rank > b.last {
+		b.last = rank
+	}
+}
+
+// Validate returns an error if the Ballot could not be built, if it has no
+// ranked choices or, if the voting is not nil, if it ranks a choice that is
+// not one of the voting's choices. Errors match ErrChoiceRequired,
+// ErrBallotRequired or ErrInvalidData, the same as the ones returned by the
+// API.
+func (b *Ballot) Validate(v *Voting) error {
+	if b.err != nil {
+		return b.err
+	}
+	if len(b.ranks) == 0 {
+		return ErrBallotRequired
+	}
+	if v == nil {
+		return nil
+	}
+	choices := make(map[string]struct{}, len(v.Choices))
+	for _, c := range v.Choices {
+		choices[c] = struct{}{}
+	}
+	for _, c := range b.order {
+		if _, ok := choices[c]; !ok {
+			return fmt.Errorf("%w: unknown choice %q", ErrInvalidData, c)
+		}
+	}
+	return nil
+}
+
+// Map returns ranks of choices that can be passed to the VotingsService.Vote
+// method.
+func (b *Ballot) Map() map[string]int {
+	m := make(map[string]int, len(b.ranks))
+	for c, r := range b.ranks {
+		m[c] = r
+	}
+	return m
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"testing"
+
+	"directdecisions.com/directdecisions"
+)
+
+func TestBallot(t *testing.T) {
+	voting := &directdecisions.Voting{
+		ID:      "40f80454800b2bd7c172",
+		Choices: []string{"Margarita", "Pepperoni", "Capricciosa", "Quattro Formaggi"},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		ballot *directdecisions.Ballot
+		voting *directdecisions.Voting
+		want   map[string]int
+		err    error
+	}{
+		{
+			name:   "rank",
+			ballot: directdecisions.NewBallot().Rank("Pepperoni", "Margarita"),
+			voting: voting,
+			want:   map[string]int{"Pepperoni": 1, "Margarita": 2},
+		},
+		{
+			name:   "tie",
+			ballot: directdecisions.NewBallot().Rank("Pepperoni").Tie("Margarita", "Capricciosa").Rank("Quattro Formaggi"),
+			voting: voting,
+			want:   map[string]int{"Pepperoni": 1, "Margarita": 2, "Capricciosa": 2, "Quattro Formaggi": 3},
+		},
+		{
+			name:   "set",
+			ballot: directdecisions.NewBallot().Set("Margarita", 5).Rank("Pepperoni"),
+			voting: voting,
+			want:   map[string]int{"Margarita": 5, "Pepperoni": 6},
+		},
+		{
+			name:   "without voting",
+			ballot: directdecisions.NewBallot().Rank("Hawaiian"),
+			want:   map[string]int{"Hawaiian": 1},
+		},
+		{
+			name:   "empty",
+			ballot: directdecisions.NewBallot(),
+			voting: voting,
+			want:   map[string]int{},
+			err:    directdecisions.ErrBallotRequired,
+		},
+		{
+			name:   "unknown choice",
+			ballot: directdecisions.NewBallot().Rank("Margarita", "Hawaiian"),
+			voting: voting,
+			want:   map[string]int{"Margarita": 1, "Hawaiian": 2},
+			err:    directdecisions.ErrInvalidData,
+		},
+		{
+			name:   "duplicate choice",
+			ballot: directdecisions.NewBallot().Rank("Margarita").Tie("Pepperoni", "Margarita"),
+			voting: voting,
+			want:   map[string]int{"Margarita": 1, "Pepperoni": 2},
+			err:    directdecisions.ErrInvalidData,
+		},
+		{
+			name:   "invalid rank",
+			ballot: directdecisions.NewBallot().Set("Margarita", 0),
+			voting: voting,
+			want:   map[string]int{},
+			err:    directdecisions.ErrInvalidData,
+		},
+		{
+			name:   "empty choice",
+			ballot: directdecisions.NewBallot().Rank(""),
+			voting: voting,
+			want:   map[string]int{},
+			err:    directdecisions.ErrChoiceRequired,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assertErrors(t, tc.ballot.Validate(tc.voting), tc.err)
+			assertEqual(t, "ballot", tc.ballot.Map(), tc.want)
+		})
+	}
+}
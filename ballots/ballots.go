@@ -0,0 +1,103 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ballots reads ballots of many voters from CSV and JSON Lines files
+// in the form that can be submitted with directdecisions VotingsService Vote
+// and VoteMany methods.
+package ballots
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"directdecisions.com/directdecisions"
+)
+
+// Reader reads ballots one by one. Read returns io.EOF when there are no more
+// ballots. If Read returns an *Error, the ballot on that line is invalid and
+// reading can continue with the next one. Any other error is not
+// recoverable.
+type Reader interface {
+	Read() (directdecisions.VoterBallot, error)
+}
+
+// Error is an error of a single line of the input.
+type Error struct {
+	Line    int    // Line number starting from 1.
+	VoterID string // Voter ID, if it is known.
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.VoterID != "" {
+		return fmt.Sprintf("line %v: voter %q: %v", e.Line, e.VoterID, e.Err)
+	}
+	return fmt.Sprintf("line %v: %v", e.Line, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ReadAll reads all valid ballots from the Reader. Errors of invalid lines
+// are joined in the returned error, so that the valid ballots can still be
+// submitted and the invalid ones reported. If an error that is not an *Error
+// is encountered, reading stops and it is returned alone.
+func ReadAll(r Reader) (ballots []directdecisions.VoterBallot, err error) {
+	var errs []error
+	for {
+		b, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var lineErr *Error
+			if !errors.As(err, &lineErr) {
+				return ballots, err
+			}
+			errs = append(errs, err)
+			continue
+		}
+		ballots = append(ballots, b)
+	}
+	return ballots, errors.Join(errs...)
+}
+
+// validator checks ballots against the voting and voter IDs for duplicates.
+type validator struct {
+	voting *directdecisions.Voting
+	voters map[string]int // line numbers by voter ID
+}
+
+func newValidator(v *directdecisions.Voting) *validator {
+	return &validator{
+		voting: v,
+		voters: make(map[string]int),
+	}
+}
+
+// validate returns the VoterBallot or an *Error if the voter ID is empty or
+// seen on another line, or if the ballot is not valid for the voting.
+func (v *validator) validate(line int, voterID string, b *directdecisions.Ballot) (directdecisions.VoterBallot, error) {
+	if voterID == "" {
+		return directdecisions.VoterBallot{}, &Error{Line: line, Err: directdecisions.ErrInvalidVoterID}
+	}
+	if l, ok := v.voters[voterID]; ok {
+		return directdecisions.VoterBallot{}, &Error{
+			Line:    line,
+			VoterID: voterID,
+			Err:     fmt.Errorf("%w: duplicate voter of line %v", directdecisions.ErrInvalidData, l),
+		}
+	}
+	if err := b.Validate(v.voting); err != nil {
+		return directdecisions.VoterBallot{}, &Error{Line: line, VoterID: voterID, Err: err}
+	}
+	v.voters[voterID] = line
+	return directdecisions.VoterBallot{
+		VoterID: voterID,
+		Ballot:  b.Map(),
+	}, nil
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ballots
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"directdecisions.com/directdecisions"
+)
+
+// Layout is the arrangement of ballot columns in a CSV file.
+type Layout int
+
+const (
+	// Ranks layout has a header with choices and each row holds the ranks
+	// of choices in their columns. Empty cells leave choices unranked.
+	Ranks Layout = iota
+	// Preferences layout has rows with choices ordered from the most
+	// preferred one. The header is only skipped and empty cells are ignored.
+	Preferences
+)
+
+// CSVOptions holds optional parameters for the CSVReader.
+type CSVOptions struct {
+	// Layout is the arrangement of ballot columns. If it is not set, Ranks
+	// layout is used.
+	Layout Layout
+	// VoterIDColumn is the header name of the column with voter IDs. If it
+	// is empty, the first column is used.
+	VoterIDColumn string
+	// Comma is the field delimiter. If it is zero, ',' is used.
+	Comma rune
+}
+
+// CSVReader reads ballots from a CSV file with a header row and one row per
+// voter.
+type CSVReader struct {
+	r       *csv.Reader
+	layout  Layout
+	voterID int      // index of the voter ID column
+	header  []string // choices by column index for Ranks layout
+	v       *validator
+}
+
+// NewCSVReader reads the header and returns a new CSVReader. If the voting is
+// not nil, the header and ballots are validated against its choices. An
+// *Error is returned if the header is not valid.
+func NewCSVReader(r io.Reader, v *directdecisions.Voting, o *CSVOptions) (*CSVReader, error) {
+	if o == nil {
+		o = new(CSVOptions)
+	}
+	cr := csv.NewReader(r)
+	if o.Comma != 0 {
+		cr.Comma = o.Comma
+	}
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, &Error{Line: 1, Err: fmt.Errorf("%w: missing header", directdecisions.ErrInvalidData)}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &CSVReader{
+		r:      cr,
+		layout: o.Layout,
+		v:      newValidator(v),
+	}
+
+	if o.VoterIDColumn != "" {
+		c.voterID = -1
+		for i, h := range header {
+			if strings.TrimSpace(h) == o.VoterIDColumn {
+				c.voterID = i
+				break
+			}
+		}
+		if c.voterID < 0 {
+			return nil, &Error{Line: 1, Err: fmt.Errorf("%w: missing voter id column %q", directdecisions.ErrInvalidData, o.VoterIDColumn)}
+		}
+	}
+
+	if c.layout == Ranks {
+		if err := c.setChoices(header, v); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// setChoices validates choices in the header for the Ranks layout.
+func (c *CSVReader) setChoices(header []string, v *directdecisions.Voting) error {
+	var known map[string]struct{}
+	if v != nil {
+		known = make(map[string]struct{}, len(v.Choices))
+		for _, choice := range v.Choices {
+			known[choice] = struct{}{}
+		}
+	}
+	seen := make(map[string]struct{}, len(header))
+	c.header = make([]string, len(header))
+	for i, h := range header {
+		if i == c.voterID {
+			continue
+		}
+		h = strings.TrimSpace(h)
+		if h == "" {
+			return &Error{Line: 1, Err: directdecisions.ErrChoiceRequired}
+		}
+		if _, ok := seen[h]; ok {
+			return &Error{Line: 1, Err: fmt.Errorf("%w: duplicate choice %q", directdecisions.ErrInvalidData, h)}
+		}
+		seen[h] = struct{}{}
+		if known != nil {
+			if _, ok := known[h]; !ok {
+				return &Error{Line: 1, Err: fmt.Errorf("%w: unknown choice %q", directdecisions.ErrInvalidData, h)}
+			}
+		}
+		c.header[i] = h
+	}
+	return nil
+}
+
+// Read returns the ballot from the next row.
+func (c *CSVReader) Read() (directdecisions.VoterBallot, error) {
+	record, err := c.r.Read()
+	if err != nil {
+		return directdecisions.VoterBallot{}, err
+	}
+	line, _ := c.r.FieldPos(0)
+
+	if c.voterID >= len(record) {
+		return directdecisions.VoterBallot{}, &Error{Line: line, Err: directdecisions.ErrInvalidVoterID}
+	}
+	voterID := strings.TrimSpace(record[c.voterID])
+
+	b := directdecisions.NewBallot()
+	switch c.layout {
+	case Ranks:
+		if len(record) != len(c.header) {
+			return directdecisions.VoterBallot{}, &Error{
+				Line:    line,
+				VoterID: voterID,
+				Err:     fmt.Errorf("%w: got %v columns, want %v", directdecisions.ErrInvalidData, len(record), len(c.header)),
+			}
+		}
+		for i, cell := range record {
+			cell = strings.TrimSpace(cell)
+			if i == c.voterID || cell == "" {
+				continue
+			}
+			rank, err := strconv.Atoi(cell)
+			if err != nil {
+				return directdecisions.VoterBallot{}, &Error{
+					Line:    line,
+					VoterID: voterID,
+					Err:     fmt.Errorf("%w: invalid rank %q for choice %q", directdecisions.ErrInvalidData, cell, c.header[i]),
+				}
+			}
+			b.Set(c.header[i], rank)
+		}
+	case Preferences:
+		for i, cell := range record {
+			cell = strings.TrimSpace(cell)
+			if i == c.voterID || cell == "" {
+				continue
+			}
+			b.Rank(cell)
+		}
+	}
+
+	return c.v.validate(line, voterID, b)
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ballots_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"directdecisions.com/directdecisions"
+	"directdecisions.com/directdecisions/ballots"
+)
+
+var voting = &directdecisions.Voting{
+	ID:      "40f80454800b2bd7c172",
+	Choices: []string{"Margarita", "Pepperoni", "Capricciosa"},
+}
+
+func TestCSVReader_ranks(t *testing.T) {
+	r, err := ballots.NewCSVReader(strings.NewReader(`voter,Margarita,Pepperoni,Capricciosa
+leonardo,1,2,
+michelangelo,2,1,1
+raphael,x,1,2
+donatello,,,
+leonardo,1,,
+splinter,1,2
+april,0,1,2
+`), voting, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ballots.ReadAll(r)
+	assertEqual(t, "ballots", got, []directdecisions.VoterBallot{
+		{VoterID: "leonardo", Ballot: map[string]int{"Margarita": 1, "Pepperoni": 2}},
+		{VoterID: "michelangelo", Ballot: map[string]int{"Margarita": 2, "Pepperoni": 1, "Capricciosa": 1}},
+	})
+	assertLineErrors(t, err, []lineError{
+		{line: 4, voterID: "raphael", err: directdecisions.ErrInvalidData},
+		{line: 5, voterID: "donatello", err: directdecisions.ErrBallotRequired},
+		{line: 6, voterID: "leonardo", err: directdecisions.ErrInvalidData},
+		{line: 7, voterID: "splinter", err: directdecisions.ErrInvalidData},
+		{line: 8, voterID: "april", err: directdecisions.ErrInvalidData},
+	})
+}
+
+func TestCSVReader_preferences(t *testing.T) {
+	r, err := ballots.NewCSVReader(strings.NewReader(`first;second;third;id
+Pepperoni;Margarita;;leonardo
+Capricciosa;;;michelangelo
+Hawaiian;;;raphael
+`), voting, &ballots.CSVOptions{
+		Layout:        ballots.Preferences,
+		VoterIDColumn: "id",
+		Comma:         ';',
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ballots.ReadAll(r)
+	assertEqual(t, "ballots", got, []directdecisions.VoterBallot{
+		{VoterID: "leonardo", Ballot: map[string]int{"Pepperoni": 1, "Margarita": 2}},
+		{VoterID: "michelangelo", Ballot: map[string]int{"Capricciosa": 1}},
+	})
+	assertLineErrors(t, err, []lineError{
+		{line: 4, voterID: "raphael", err: directdecisions.ErrInvalidData},
+	})
+}
+
+func TestNewCSVReader_header(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		input   string
+		options *ballots.CSVOptions
+		err     error
+	}{
+		{
+			name:  "empty",
+			input: "",
+			err:   directdecisions.ErrInvalidData,
+		},
+		{
+			name:  "unknown choice",
+			input: "voter,Margarita,Hawaiian\n",
+			err:   directdecisions.ErrInvalidData,
+		},
+		{
+			name:  "duplicate choice",
+			input: "voter,Margarita,Margarita\n",
+			err:   directdecisions.ErrInvalidData,
+		},
+		{
+			name:  "empty choice",
+			input: "voter,Margarita,\n",
+			err:   directdecisions.ErrChoiceRequired,
+		},
+		{
+			name:    "missing voter id column",
+			input:   "voter,Margarita\n",
+			options: &ballots.CSVOptions{VoterIDColumn: "id"},
+			err:     directdecisions.ErrInvalidData,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ballots.NewCSVReader(strings.NewReader(tc.input), voting, tc.options)
+			var lineErr *ballots.Error
+			if !errors.As(err, &lineErr) {
+				t.Fatalf("got error %T %[1]v, want *ballots.Error", err)
+			}
+			assertEqual(t, "line", lineErr.Line, 1)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("got error %v, want %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	err := &ballots.Error{Line: 3, VoterID: "leonardo", Err: directdecisions.ErrBallotRequired}
+	assertEqual(t, "error", err.Error(), `line 3: voter "leonardo": Ballot Required`)
+
+	err = &ballots.Error{Line: 5, Err: directdecisions.ErrInvalidVoterID}
+	assertEqual(t, "error", err.Error(), `line 5: Invalid Voter ID`)
+}
+
+type lineError struct {
+	line    int
+	voterID string
+	err     error
+}
+
+// assertLineErrors checks that the joined error holds exactly the expected
+// *ballots.Error errors in order.
+func assertLineErrors(t testing.TB, err error, want []lineError) {
+	t.Helper()
+
+	var got []error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		got = joined.Unwrap()
+	} else if err != nil {
+		got = []error{err}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v errors %v, want %v", len(got), err, len(want))
+	}
+	for i, w := range want {
+		var lineErr *ballots.Error
+		if !errors.As(got[i], &lineErr) {
+			t.Fatalf("got error %T %[1]v, want *ballots.Error", got[i])
+		}
+		assertEqual(t, fmt.Sprintf("error %v line", i), lineErr.Line, w.line)
+		assertEqual(t, fmt.Sprintf("error %v voter id", i), lineErr.VoterID, w.voterID)
+		if !errors.Is(lineErr, w.err) {
+			t.Errorf("got error %v, want %v", lineErr, w.err)
+		}
+	}
+}
+
+func assertEqual(t testing.TB, name string, got, want any) {
+	t.Helper()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s: got %+v, want %+v", name, got, want)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ballots
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"directdecisions.com/directdecisions"
+)
+
+// JSONLReader reads ballots from JSON Lines input where every line is an
+// object with a voter ID and a ballot in the same form as in the
+// VotingsService.Vote method:
+//
+//	{"voter_id": "leonardo", "ballot": {"Pepperoni": 1, "Margarita": 2}}
+//
+// Empty lines are skipped.
+type JSONLReader struct {
+	r    *bufio.Reader
+	line int
+	v    *validator
+}
+
+// NewJSONLReader returns a new JSONLReader. If the voting is not nil, ballots
+// are validated against its choices.
+func NewJSONLReader(r io.Reader, v *directdecisions.Voting) *JSONLReader {
+	return &JSONLReader{
+		r: bufio.NewReader(r),
+		v: newValidator(v),
+	}
+}
+
+// Read returns the ballot from the next non-empty line.
+func (j *JSONLReader) Read() (directdecisions.VoterBallot, error) {
+	for {
+		data, err := j.r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return directdecisions.VoterBallot{}, err
+		}
+		if len(data) == 0 && err == io.EOF {
+			return directdecisions.VoterBallot{}, io.EOF
+		}
+		j.line++
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		var l struct {
+			VoterID string         `json:"voter_id"`
+			Ballot  map[string]int `json:"ballot"`
+		}
+		if err := json.Unmarshal(data, &l); err != nil {
+			return directdecisions.VoterBallot{}, &Error{Line: j.line, Err: fmt.Errorf("%w: %v", directdecisions.ErrInvalidData, err)}
+		}
+
+		choices := make([]string, 0, len(l.Ballot))
+		for c := range l.Ballot {
+			choices = append(choices, c)
+		}
+		// Report the same error for the same line every time.
+		sort.Strings(choices)
+		b := directdecisions.NewBallot()
+		for _, c := range choices {
+			b.Set(c, l.Ballot[c])
+		}
+		return j.v.validate(j.line, l.VoterID, b)
+	}
+}
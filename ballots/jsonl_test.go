@@ -0,0 +1,38 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ballots_test
+
+import (
+	"strings"
+	"testing"
+
+	"directdecisions.com/directdecisions"
+	"directdecisions.com/directdecisions/ballots"
+)
+
+func TestJSONLReader(t *testing.T) {
+	r := ballots.NewJSONLReader(strings.NewReader(`{"voter_id": "leonardo", "ballot": {"Pepperoni": 1, "Margarita": 2}}
+
+{"voter_id": "michelangelo", "ballot": {"Hawaiian": 1}}
+{"voter_id": "raphael", "ballot": {"Capricciosa": -1}}
+{"voter_id": "", "ballot": {"Capricciosa": 1}}
+{"voter_id": "donatello", "ballot": {}}
+not json
+{"voter_id": "splinter", "ballot": {"Capricciosa": 1, "Margarita": 1}}`), voting)
+
+	got, err := ballots.ReadAll(r)
+	assertEqual(t, "ballots", got, []directdecisions.VoterBallot{
+		{VoterID: "leonardo", Ballot: map[string]int{"Pepperoni": 1, "Margarita": 2}},
+		{VoterID: "splinter", Ballot: map[string]int{"Capricciosa": 1, "Margarita": 1}},
+	})
+	assertLineErrors(t, err, []lineError{
+		{line: 3, voterID: "michelangelo", err: directdecisions.ErrInvalidData},
+		{line: 4, voterID: "raphael", err: directdecisions.ErrInvalidData},
+		{line: 5, err: directdecisions.ErrInvalidVoterID},
+		{line: 6, voterID: "donatello", err: directdecisions.ErrBallotRequired},
+		{line: 7, err: directdecisions.ErrInvalidData},
+	})
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import (
+	"context"
+	"errors"
+)
+
+const defaultVoteConcurrency = 4
+
+// VoterBallot is a ballot of a single voter.
+type VoterBallot struct {
+	VoterID string
+	Ballot  map[string]int
+}
+
+// VoteManyOptions holds optional parameters for VoteMany and VoteStream
+// methods.
+type VoteManyOptions struct {
+	// Concurrency is the maximal number of ballots submitted in parallel. It
+	// is reduced to the number of remaining requests in the current rate
+	// limit window. If it is zero, 4 is used.
+	Concurrency int
+}
+
+// VoteResult is the result of submitting a single ballot.
+type VoteResult struct {
+	VoterID  string
+	Revoted  bool  // The voter has already voted and the ballot is replaced.
+	Attempts int   // Number of sent HTTP requests, more than one if retried.
+	Err      error // Error returned by the Vote method.
+}
+
+// VoteManyReport holds results of all submitted ballots.
+type VoteManyReport struct {
+	// Results of submitted ballots in the order in which they were provided.
+	Results []VoteResult
+	// Pending are ballots that were not submitted, or whose submission was
+	// interrupted, because the context was canceled. They can be passed to
+	// VoteMany to resume the submission.
+	Pending []VoterBallot
+
+	Voted   int // Number of successfully submitted ballots, including revotes.
+	Revoted int // Number of successfully submitted ballots that replaced previous ones.
+	Failed  int // Number of ballots that were submitted with an error.
+}
+
+// VoteMany submits ballots of many voters in parallel. Errors of individual
+// ballots are reported in VoteManyReport Results, and the returned error is
+// only the context error if the submission is canceled, in which case
+// ballots that were not submitted are in VoteManyReport Pending.
+func (s *VotingsService) VoteMany(ctx context.Context, votingID string, ballots []VoterBallot, o *VoteManyOptions) (*VoteManyReport, error) {
+	i := 0
+	return s.voteMany(ctx, votingID, func() (b VoterBallot, ok bool) {
+		if i >= len(ballots) {
+			return b, false
+		}
+		b = ballots[i]
+		i++
+		return b, true
+	}, o)
+}
+
+// VoteStream submits ballots received from the channel in parallel until it
+// is closed. It is the same as VoteMany, except that ballots left in the
+// channel after the context is canceled are not in VoteManyReport Pending.
+func (s *VotingsService) VoteStream(ctx context.Context, votingID string, ballots <-chan VoterBallot, o *VoteManyOptions) (*VoteManyReport, error) {
+	return s.voteMany(ctx, votingID, func() (b VoterBallot, ok bool) {
+		select {
+		case b, ok = <-ballots:
+			return b, ok
+		case <-ctx.Done():
+			return b, false
+		}
+	}, o)
+}
+
+func (s *VotingsService) voteMany(ctx context.Context, votingID string, next func() (VoterBallot, bool), o *VoteManyOptions) (*VoteManyReport, error) {
+	concurrency := defaultVoteConcurrency
+	if o != nil && o.Concurrency > 0 {
+		concurrency = o.Concurrency
+	}
+
+	type entry struct {
+		ballot  VoterBallot
+		result  VoteResult
+		pending bool
+	}
+
+	type indexedResult struct {
+		index  int
+		result VoteResult
+	}
+
+	// The Idempotency from the context can not be shared by concurrent
+	// operations with different ballots.
+	ctx = contextWithoutIdempotency(ctx)
+
+	var entries []entry
+	done := make(chan indexedResult)
+	running := 0
+	collect := func() {
+		r := <-done
+		running--
+		e := &entries[r.index]
+		e.result = r.result
+		// Ballots that were interrupted by the cancellation can be resubmitted
+		// as voting again only replaces the ballot.
+		e.pending = ctx.Err() != nil && errors.Is(r.result.Err, ctx.Err())
+	}
+
+	for {
+		b, ok := next()
+		if !ok {
+			break
+		}
+		for running > 0 && running >= s.client.voteConcurrency(concurrency) {
+			collect()
+		}
+		index := len(entries)
+		entries = append(entries, entry{ballot: b})
+		if ctx.Err() != nil {
+			entries[index].pending = true
+			continue
+		}
+		running++
+		go func() {
+			var attempts int
+			revoted, err := s.Vote(contextWithAttempts(ctx, &attempts), votingID, b.VoterID, b.Ballot)
+			done <- indexedResult{
+				index: index,
+				result: VoteResult{
+					VoterID:  b.VoterID,
+					Revoted:  revoted,
+					Attempts: attempts,
+					Err:      err,
+				},
+			}
+		}()
+	}
+	for running > 0 {
+		collect()
+	}
+
+	report := new(VoteManyReport)
+	for _, e := range entries {
+		switch {
+		case e.pending:
+			report.Pending = append(report.Pending, e.ballot)
+			continue
+		case e.result.Err != nil:
+			report.Failed++
+		case e.result.Revoted:
+			report.Revoted++
+			report.Voted++
+		default:
+			report.Voted++
+		}
+		report.Results = append(report.Results, e.result)
+	}
+	return report, ctx.Err()
+}
+
+// voteConcurrency returns the number of requests that can be sent in
+// parallel without exceeding the remaining rate limit.
+func (c *Client) voteConcurrency(max int) int {
+	r := c.Rate()
+	if r.Limit == 0 || r.Remaining >= max {
+		return max
+	}
+	if r.Remaining < 1 {
+		return 1
+	}
+	return r.Remaining
+}
+
+type attemptsKey struct{}
+
+// contextWithAttempts returns a new context that makes the Client store the
+// number of sent HTTP requests of an operation into the attempts variable.
+func contextWithAttempts(ctx context.Context, attempts *int) context.Context {
+	return context.WithValue(ctx, attemptsKey{}, attempts)
+}
+
+// recordAttempts stores the number of sent HTTP requests of the operation
+// into the variable from the context set by contextWithAttempts.
+func recordAttempts(ctx context.Context, call *Call) {
+	attempts, ok := ctx.Value(attemptsKey{}).(*int)
+	if !ok || call == nil {
+		return
+	}
+	*attempts = call.sent
+}
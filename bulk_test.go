@@ -0,0 +1,183 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"directdecisions.com/directdecisions"
+)
+
+const bulkVotingPath = "/v1/votings/40f80454800b2bd7c172/ballots/"
+
+func TestVotingsService_VoteMany(t *testing.T) {
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Retry: &directdecisions.RetryPolicy{
+			MinBackoff: time.Millisecond,
+			MaxBackoff: time.Millisecond,
+		},
+	})
+
+	var unavailable atomic.Bool
+	mux.HandleFunc(bulkVotingPath, requireMethod("POST", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", jsonContentType)
+		switch strings.TrimPrefix(r.URL.Path, bulkVotingPath) {
+		case "leonardo":
+			_, _ = w.Write([]byte(`{"revoted": true}`))
+		case "raphael":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "Bad Request", "code": 400, "errors": ["Invalid Data"]}`))
+		case "donatello":
+			if unavailable.CompareAndSwap(false, true) {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			_, _ = w.Write([]byte(`{"revoted": false}`))
+		default:
+			_, _ = w.Write([]byte(`{"revoted": false}`))
+		}
+	}))
+
+	report, err := client.Votings.VoteMany(context.Background(), "40f80454800b2bd7c172", []directdecisions.VoterBallot{
+		{VoterID: "leonardo", Ballot: map[string]int{"Margarita": 1}},
+		{VoterID: "michelangelo", Ballot: map[string]int{"Pepperoni": 1}},
+		{VoterID: "raphael", Ballot: map[string]int{"Hawaiian": 1}},
+		{VoterID: "donatello", Ballot: map[string]int{"Margarita": 1}},
+	}, &directdecisions.VoteManyOptions{Concurrency: 2})
+	assertErrors(t, err, nil)
+
+	assertEqual(t, "voted", report.Voted, 3)
+	assertEqual(t, "revoted", report.Revoted, 1)
+	assertEqual(t, "failed", report.Failed, 1)
+	assertEqual(t, "pending", len(report.Pending), 0)
+
+	if len(report.Results) != 4 {
+		t.Fatalf("got %v results, want 4", len(report.Results))
+	}
+	for i, want := range []struct {
+		voterID  string
+		revoted  bool
+		attempts int
+		err      error
+	}{
+		{voterID: "leonardo", revoted: true, attempts: 1},
+		{voterID: "michelangelo", attempts: 1},
+		{voterID: "raphael", attempts: 1, err: directdecisions.ErrInvalidData},
+		{voterID: "donatello", attempts: 2},
+	} {
+		got := report.Results[i]
+		assertEqual(t, "voter id", got.VoterID, want.voterID)
+		assertEqual(t, "revoted", got.Revoted, want.revoted)
+		assertEqual(t, "attempts", got.Attempts, want.attempts)
+		assertErrors(t, got.Err, want.err)
+		if want.err == nil && got.Err != nil {
+			t.Errorf("got error %v for voter %s", got.Err, got.VoterID)
+		}
+	}
+}
+
+func TestVotingsService_VoteMany_canceled(t *testing.T) {
+	client, mux, _ := newClient(t, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mux.HandleFunc(bulkVotingPath, requireMethod("POST", func(w http.ResponseWriter, r *http.Request) {
+		if strings.TrimPrefix(r.URL.Path, bulkVotingPath) == "michelangelo" {
+			// Read the body to detect the closed connection.
+			_, _ = io.Copy(io.Discard, r.Body)
+			cancel()
+			<-r.Context().Done()
+			return
+		}
+		w.Header().Set("Content-Type", jsonContentType)
+		_, _ = w.Write([]byte(`{"revoted": false}`))
+	}))
+
+	ballots := []directdecisions.VoterBallot{
+		{VoterID: "leonardo", Ballot: map[string]int{"Margarita": 1}},
+		{VoterID: "michelangelo", Ballot: map[string]int{"Pepperoni": 1}},
+		{VoterID: "raphael", Ballot: map[string]int{"Capricciosa": 1}},
+	}
+
+	report, err := client.Votings.VoteMany(ctx, "40f80454800b2bd7c172", ballots, &directdecisions.VoteManyOptions{Concurrency: 1})
+	assertErrors(t, err, context.Canceled)
+
+	assertEqual(t, "voted", report.Voted, 1)
+	assertEqual(t, "failed", report.Failed, 0)
+	assertEqual(t, "results", len(report.Results), 1)
+	assertEqual(t, "pending", report.Pending, ballots[1:])
+
+	// Resume the submission.
+	mux.HandleFunc(bulkVotingPath+"michelangelo", requireMethod("POST", newStaticHandler(`{"revoted": false}`)))
+
+	report, err = client.Votings.VoteMany(context.Background(), "40f80454800b2bd7c172", report.Pending, nil)
+	assertErrors(t, err, nil)
+	assertEqual(t, "voted", report.Voted, 2)
+	assertEqual(t, "pending", len(report.Pending), 0)
+}
+
+func TestVotingsService_VoteStream(t *testing.T) {
+	client, mux, _ := newClient(t, "")
+
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "1")
+		w.Header().Set("Content-Type", jsonContentType)
+		_, _ = w.Write([]byte(`{"id": "40f80454800b2bd7c172"}`))
+	}))
+
+	var (
+		mu                sync.Mutex
+		running, inFlight int
+	)
+	mux.HandleFunc(bulkVotingPath, requireMethod("POST", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		running++
+		if running > inFlight {
+			inFlight = running
+		}
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "1")
+		w.Header().Set("Content-Type", jsonContentType)
+		_, _ = w.Write([]byte(`{"revoted": false}`))
+	}))
+
+	// Set the remaining rate limit that reduces the concurrency.
+	if _, err := client.Votings.Voting(context.Background(), "40f80454800b2bd7c172"); err != nil {
+		t.Fatal(err)
+	}
+
+	ballots := make(chan directdecisions.VoterBallot)
+	go func() {
+		defer close(ballots)
+		for _, id := range []string{"leonardo", "michelangelo", "raphael", "donatello", "splinter"} {
+			ballots <- directdecisions.VoterBallot{VoterID: id, Ballot: map[string]int{"Margarita": 1}}
+		}
+	}()
+
+	report, err := client.Votings.VoteStream(context.Background(), "40f80454800b2bd7c172", ballots, &directdecisions.VoteManyOptions{Concurrency: 8})
+	assertErrors(t, err, nil)
+
+	assertEqual(t, "voted", report.Voted, 5)
+	assertEqual(t, "max in flight", inFlight, 1)
+	assertEqual(t, "last voter id", report.Results[4].VoterID, "splinter")
+}
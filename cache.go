@@ -0,0 +1,214 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Cache stores responses to GET requests keyed by API request paths prefixed
+// with a hash of the API base URL and the credentials, such as
+// "5d41402abc4b2a76/v1/votings/40f80454800b2bd7c172/results", so that
+// responses are not shared between different APIs, accounts and keys.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored under the key and whether it is found.
+	Get(key string) (value []byte, ok bool)
+	// Set stores the value under the key.
+	Set(key string, value []byte)
+	// Delete removes the value stored under the key and the values stored
+	// under keys that start with the key followed by a slash.
+	Delete(key string)
+}
+
+// CacheOptions configures caching of responses to GET requests, such as the
+// ones of VotingsService Voting, Results and Duels methods. Cached responses
+// of a voting are removed when it is changed or deleted by the Client.
+type CacheOptions struct {
+	// TTL is the duration for which a cached response is used without
+	// sending a request. After it expires, the request is sent with
+	// If-None-Match and If-Modified-Since headers and a 304 Not Modified
+	// response is served from the cache. If it is zero, cached responses are
+	// always revalidated.
+	TTL time.Duration
+	// Storage stores cached responses. If it is nil, a new MemoryCache with
+	// MaxEntries limit is used.
+	Storage Cache
+	// MaxEntries is the maximal number of cached responses in the default
+	// storage.
+	MaxEntries int
+}
+
+// cache stores GET responses in the Cache storage.
+type cache struct {
+	ttl     time.Duration
+	storage Cache
+}
+
+// cacheEntry is a stored response.
+type cacheEntry struct {
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+	Stored time.Time   `json:"stored"`
+}
+
+// cachedHeaders are response headers that are stored with the response body.
+var cachedHeaders = []string{"Content-Type", "ETag", "Last-Modified"}
+
+func newCache(o *CacheOptions) *cache {
+	if o == nil {
+		return nil
+	}
+	storage := o.Storage
+	if storage == nil {
+		storage = NewMemoryCache(o.MaxEntries)
+	}
+	return &cache{
+		ttl:     o.TTL,
+		storage: storage,
+	}
+}
+
+// cacheScope returns the prefix of cache keys that separates responses to
+// requests sent to the Client's base URL with the Client's credentials, and
+// the API key for the request. Credentials that can not be identified
+// without it are identified by the API key, which is resolved if the passed
+// key is empty.
+func (c *Client) cacheScope(ctx context.Context, key string) (scope, resolvedKey string, err error) {
+	if c.cache == nil {
+		return "", key, nil
+	}
+	id, ok := c.credentialsID()
+	if !ok {
+		if key == "" {
+			if key, err = c.key(ctx); err != nil {
+				return "", "", err
+			}
+		}
+		id = key
+	}
+	h := sha256.Sum256([]byte(c.baseURL.String() + "\n" + id))
+	return hex.EncodeToString(h[:8]), key, nil
+}
+
+// lookup returns the cached response for a request with the method under the
+// key and whether it can be used without sending the request.
+func (c *cache) lookup(method, key string) (e *cacheEntry, fresh bool) {
+	if c == nil || method != http.MethodGet {
+		return nil, false
+	}
+	b, ok := c.storage.Get(key)
+	if !ok {
+		return nil, false
+	}
+	e = new(cacheEntry)
+	if err := json.Unmarshal(b, e); err != nil {
+		// Values that are not decodable are ignored and overwritten.
+		return nil, false
+	}
+	return e, time.Since(e.Stored) < c.ttl
+}
+
+// store saves the body of a successful response to the GET request of the
+// call, replacing the response body with a reader of the same content.
+func (c *cache) store(call *Call, r *http.Response) error {
+	if c == nil || call.Request.Method != http.MethodGet || r.StatusCode != http.StatusOK {
+		return nil
+	}
+	if strings.Contains(r.Header.Get("Cache-Control"), "no-store") {
+		return nil
+	}
+	if c.ttl == 0 && r.Header.Get("ETag") == "" && r.Header.Get("Last-Modified") == "" {
+		// Such response could be neither used nor revalidated.
+		return nil
+	}
+
+	b, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(b))
+
+	header := make(http.Header)
+	for _, k := range cachedHeaders {
+		if v := r.Header.Get(k); v != "" {
+			header.Set(k, v)
+		}
+	}
+	c.set(call.cacheKey, &cacheEntry{
+		Header: header,
+		Body:   b,
+		Stored: time.Now(),
+	})
+	return nil
+}
+
+// refresh stores the entry again as if its response is just received.
+func (c *cache) refresh(key string, e *cacheEntry) {
+	r := *e
+	r.Stored = time.Now()
+	c.set(key, &r)
+}
+
+func (c *cache) set(key string, e *cacheEntry) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	c.storage.Set(key, b)
+}
+
+// invalidate removes all cached responses in the scope of the voting that is
+// changed by a request with the method on the path.
+func (c *cache) invalidate(method, scope, path string) {
+	if c == nil || method == http.MethodGet {
+		return
+	}
+	if votingIDFromPath(path) == "" {
+		return
+	}
+	// Responses of the voting have paths that start with the voting path.
+	parts := strings.SplitN(path, "/", 4)
+	c.storage.Delete(scope + "/" + strings.Join(parts[:3], "/"))
+}
+
+// setConditionalHeaders sets headers to the request that make the API respond
+// with 304 Not Modified if the cached response is still valid.
+func setConditionalHeaders(r *http.Request, e *cacheEntry) {
+	if e == nil {
+		return
+	}
+	if v := e.Header.Get("ETag"); v != "" {
+		r.Header.Set("If-None-Match", v)
+	}
+	if v := e.Header.Get("Last-Modified"); v != "" {
+		r.Header.Set("If-Modified-Since", v)
+	}
+}
+
+// response constructs a new response to the request from the cached one.
+func (e *cacheEntry) response(r *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       r,
+	}
+}
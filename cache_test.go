@@ -0,0 +1,282 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"testing"
+	"time"
+
+	"directdecisions.com/directdecisions"
+)
+
+func TestCache_ttl(t *testing.T) {
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Cache: &directdecisions.CacheOptions{TTL: time.Hour},
+	})
+
+	var requests int
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		newStaticHandler(`{"id":"40f80454800b2bd7c172","choices":["Margarita","Diavola","Capricciosa"]}`)(w, r)
+	}))
+
+	for i := 0; i < 3; i++ {
+		got, err := client.Votings.Voting(context.Background(), "40f80454800b2bd7c172")
+		assertErrors(t, err, nil)
+		assertEqual(t, "voting", got, votingsServiceVotingWant)
+	}
+	assertEqual(t, "requests", requests, 1)
+}
+
+func TestCache_conditionalRequests(t *testing.T) {
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Cache: new(directdecisions.CacheOptions),
+	})
+
+	const (
+		etag         = `"r1"`
+		lastModified = "Wed, 21 Oct 2015 07:28:00 GMT"
+	)
+
+	var requests, notModified int
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172/results", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag && r.Header.Get("If-Modified-Since") == lastModified {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		newStaticHandler(`{"results":[{"choice":"Margarita","index":0,"wins":1,"percentage":100}],"tie":false}`)(w, r)
+	}))
+
+	for i := 0; i < 3; i++ {
+		results, tie, err := client.Votings.Results(context.Background(), "40f80454800b2bd7c172")
+		assertErrors(t, err, nil)
+		assertEqual(t, "tie", tie, false)
+		assertEqual(t, "results", results, []directdecisions.Result{
+			{Choice: "Margarita", Index: 0, Wins: 1, Percentage: 100},
+		})
+	}
+	assertEqual(t, "requests", requests, 3)
+	assertEqual(t, "not modified", notModified, 2)
+}
+
+func TestCache_notCached(t *testing.T) {
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Cache: new(directdecisions.CacheOptions),
+	})
+
+	var requests int
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") != "" {
+			t.Error("unexpected conditional request")
+		}
+		newStaticHandler(`{"id":"40f80454800b2bd7c172","choices":["Margarita","Diavola","Capricciosa"]}`)(w, r)
+	}))
+
+	// Responses without validators cannot be revalidated.
+	for i := 0; i < 2; i++ {
+		_, err := client.Votings.Voting(context.Background(), "40f80454800b2bd7c172")
+		assertErrors(t, err, nil)
+	}
+	assertEqual(t, "requests", requests, 2)
+}
+
+func TestCache_invalidation(t *testing.T) {
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Cache: &directdecisions.CacheOptions{TTL: time.Hour},
+	})
+
+	requests := make(map[string]int)
+	for _, id := range []string{"40f80454800b2bd7c172", "bd7c17240f80454800b2"} {
+		id := id
+		mux.HandleFunc("/v1/votings/"+id, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				requests[r.URL.Path]++
+			}
+			newStaticHandler(`{"id":"`+id+`","choices":["Margarita","Pepperoni"]}`)(w, r)
+		})
+		mux.HandleFunc("/v1/votings/"+id+"/results", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+			requests[r.URL.Path]++
+			newStaticHandler(`{"results":[],"tie":true}`)(w, r)
+		}))
+		mux.HandleFunc("/v1/votings/"+id+"/ballots/leonardo", func(w http.ResponseWriter, r *http.Request) {
+			newStaticHandler(`{"revoted":false}`)(w, r)
+		})
+		mux.HandleFunc("/v1/votings/"+id+"/choices", requireMethod("POST", newStaticHandler(`{"choices":["Margarita"]}`)))
+	}
+
+	get := func() {
+		t.Helper()
+
+		for _, id := range []string{"40f80454800b2bd7c172", "bd7c17240f80454800b2"} {
+			_, err := client.Votings.Voting(context.Background(), id)
+			assertErrors(t, err, nil)
+			_, _, err = client.Votings.Results(context.Background(), id)
+			assertErrors(t, err, nil)
+		}
+	}
+
+	get()
+	for _, tc := range []struct {
+		name   string
+		change func() error
+	}{
+		{
+			name: "vote",
+			change: func() error {
+				_, err := client.Votings.Vote(context.Background(), "40f80454800b2bd7c172", "leonardo", map[string]int{"Margarita": 1})
+				return err
+			},
+		},
+		{
+			name: "unvote",
+			change: func() error {
+				return client.Votings.Unvote(context.Background(), "40f80454800b2bd7c172", "leonardo")
+			},
+		},
+		{
+			name: "set",
+			change: func() error {
+				_, err := client.Votings.Set(context.Background(), "40f80454800b2bd7c172", "Margarita", 0)
+				return err
+			},
+		},
+		{
+			name: "delete",
+			change: func() error {
+				return client.Votings.Delete(context.Background(), "40f80454800b2bd7c172")
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			before := maps.Clone(requests)
+
+			assertErrors(t, tc.change(), nil)
+			get()
+
+			// Only responses of the changed voting are requested again.
+			for path, want := range map[string]int{
+				"/v1/votings/40f80454800b2bd7c172":         1,
+				"/v1/votings/40f80454800b2bd7c172/results": 1,
+				"/v1/votings/bd7c17240f80454800b2":         0,
+				"/v1/votings/bd7c17240f80454800b2/results": 0,
+			} {
+				assertEqual(t, path, requests[path]-before[path], want)
+			}
+		})
+	}
+}
+
+func TestCache_maxEntries(t *testing.T) {
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Cache: &directdecisions.CacheOptions{TTL: time.Hour, MaxEntries: 2},
+	})
+
+	requests := make(map[string]int)
+	ids := []string{"40f80454800b2bd7c172", "bd7c17240f80454800b2", "800b2bd7c17240f80454"}
+	for _, id := range ids {
+		id := id
+		mux.HandleFunc("/v1/votings/"+id, requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+			requests[r.URL.Path]++
+			newStaticHandler(`{"id":"`+id+`","choices":["Margarita","Pepperoni"]}`)(w, r)
+		}))
+	}
+
+	// The first voting is evicted as the least recently used one.
+	for _, id := range append(ids, ids[1], ids[2], ids[0]) {
+		_, err := client.Votings.Voting(context.Background(), id)
+		assertErrors(t, err, nil)
+	}
+	assertEqual(t, "requests", requests, map[string]int{
+		"/v1/votings/40f80454800b2bd7c172": 2,
+		"/v1/votings/bd7c17240f80454800b2": 1,
+		"/v1/votings/800b2bd7c17240f80454": 1,
+	})
+}
+
+func TestCache_storage(t *testing.T) {
+	storage := directdecisions.NewFileCache(t.TempDir(), time.Hour)
+	o := &directdecisions.ClientOptions{
+		Cache: &directdecisions.CacheOptions{TTL: time.Hour, Storage: storage},
+	}
+	client, mux, baseURL := newClientWithOptions(t, "", o)
+
+	var requests int
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		newStaticHandler(`{"id":"40f80454800b2bd7c172","choices":["Margarita","Diavola","Capricciosa"]}`)(w, r)
+	}))
+
+	_, err := client.Votings.Voting(context.Background(), "40f80454800b2bd7c172")
+	assertErrors(t, err, nil)
+
+	// Another client with the same storage uses the cached response.
+	client = directdecisions.NewClient("", &directdecisions.ClientOptions{
+		BaseURL: baseURL,
+		Cache:   o.Cache,
+	})
+	got, err := client.Votings.Voting(context.Background(), "40f80454800b2bd7c172")
+	assertErrors(t, err, nil)
+	assertEqual(t, "voting", got, votingsServiceVotingWant)
+	assertEqual(t, "requests", requests, 1)
+}
+
+func TestCache_scope(t *testing.T) {
+	o := &directdecisions.ClientOptions{
+		Cache: &directdecisions.CacheOptions{TTL: time.Hour, Storage: directdecisions.NewMemoryCache(0)},
+	}
+	client, mux, baseURL := newClientWithOptions(t, "good-key", o)
+
+	var requests int
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer good-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		newStaticHandler(`{"id":"40f80454800b2bd7c172","choices":["Margarita","Diavola","Capricciosa"]}`)(w, r)
+	}))
+
+	_, err := client.Votings.Voting(context.Background(), "40f80454800b2bd7c172")
+	assertErrors(t, err, nil)
+
+	// Cached responses are not served to clients with other keys.
+	client = directdecisions.NewClient("bad-key", &directdecisions.ClientOptions{
+		BaseURL: baseURL,
+		Cache:   o.Cache,
+	})
+	_, err = client.Votings.Voting(context.Background(), "40f80454800b2bd7c172")
+	assertErrors(t, err, directdecisions.ErrHTTPStatusUnauthorized)
+	assertEqual(t, "requests", requests, 2)
+
+	// Cached responses are served to clients with the same key.
+	client = directdecisions.NewClient("", &directdecisions.ClientOptions{
+		BaseURL: baseURL,
+		Cache:   o.Cache,
+		Credentials: directdecisions.CredentialsFunc(func(ctx context.Context) (string, error) {
+			return "good-key", nil
+		}),
+	})
+	got, err := client.Votings.Voting(context.Background(), "40f80454800b2bd7c172")
+	assertErrors(t, err, nil)
+	assertEqual(t, "voting", got, votingsServiceVotingWant)
+	assertEqual(t, "requests", requests, 2)
+
+	// Cached responses are not served from other base URLs.
+	client, mux, _ = newClientWithOptions(t, "good-key", o)
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	_, err = client.Votings.Voting(context.Background(), "40f80454800b2bd7c172")
+	assertErrors(t, err, directdecisions.ErrHTTPStatusNotFound)
+}
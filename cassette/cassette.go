@@ -0,0 +1,148 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cassette records HTTP traffic of the directdecisions Client into
+// human-readable cassette files and replays it back, so that tests can use
+// realistic API responses without network access.
+//
+// Both Recorder and Replayer are http.RoundTripper implementations that are
+// used as the Transport of the HTTP client in directdecisions ClientOptions.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"unicode/utf8"
+)
+
+// Cassette holds recorded HTTP interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single HTTP request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded HTTP request.
+type Request struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"` // Path and query of the request URL.
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Body is an HTTP message body. It is stored in cassette files as a JSON
+// object with the body under the "json" key as a JSON value, if the body is
+// compact JSON, optionally followed by a newline that is marked by the
+// "newline" key, under the "text" key as a string if it is valid UTF-8, or
+// under the "base64" key as a base64 encoded string otherwise, so that the
+// body is loaded exactly as it was recorded.
+type Body []byte
+
+// storedBody is the representation of Body in cassette files.
+type storedBody struct {
+	JSON    json.RawMessage `json:"json,omitempty"`
+	Newline bool            `json:"newline,omitempty"`
+	Text    *string         `json:"text,omitempty"`
+	Base64  []byte          `json:"base64,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b Body) MarshalJSON() ([]byte, error) {
+	value, newline := bytes.CutSuffix(b, []byte("\n"))
+	if isCompactJSON(value) {
+		return json.Marshal(storedBody{
+			JSON:    value,
+			Newline: newline,
+		})
+	}
+	if !utf8.Valid(b) {
+		return json.Marshal(storedBody{
+			Base64: b,
+		})
+	}
+	text := string(b)
+	return json.Marshal(storedBody{
+		Text: &text,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s storedBody
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	switch {
+	case s.Text != nil:
+		*b = Body(*s.Text)
+	case s.Base64 != nil:
+		*b = s.Base64
+	case s.JSON != nil:
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, s.JSON); err != nil {
+			return err
+		}
+		if s.Newline {
+			buf.WriteByte('\n')
+		}
+		*b = buf.Bytes()
+	default:
+		return errors.New("body without json, text or base64 value")
+	}
+	return nil
+}
+
+// isCompactJSON returns true if the data is a JSON value without insignificant
+// white space and without characters that are escaped when it is written to
+// the cassette file.
+func isCompactJSON(data []byte) bool {
+	if len(data) == 0 || !json.Valid(data) {
+		return false
+	}
+	if bytes.ContainsAny(data, "<>&\u2028\u2029") {
+		return false
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return false
+	}
+	return bytes.Equal(buf.Bytes(), data)
+}
+
+// Load reads a cassette from the file.
+func Load(filename string) (*Cassette, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to the file as indented JSON.
+func (c *Cassette) Save(filename string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cassette_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"directdecisions.com/directdecisions"
+	"directdecisions.com/directdecisions/cassette"
+	"directdecisions.com/directdecisions/directdecisionstest"
+)
+
+// session performs API calls which results are compared between recording
+// and replaying.
+func session(t testing.TB, client *directdecisions.Client) []any {
+	t.Helper()
+
+	ctx := context.Background()
+
+	v, err := client.Votings.Create(ctx, []string{"Margarita", "Pepperoni", "Capricciosa"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoted, err := client.Votings.Vote(ctx, v.ID, "leonardo", map[string]int{"Pepperoni": 1, "Margarita": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, tie, err := client.Votings.Results(ctx, v.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Votings.Ballot(ctx, v.ID, "raphael")
+	if err == nil {
+		t.Fatal("got no error for missing ballot")
+	}
+	return []any{v, revoted, results, tie, err.Error()}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	server := directdecisionstest.NewServer(&directdecisionstest.Options{Key: "secret-key"})
+	defer server.Close()
+
+	recorder := cassette.NewRecorder(server.Client().Transport)
+	baseURL, _ := url.Parse(server.URL)
+
+	recorded := session(t, directdecisions.NewClient("secret-key", &directdecisions.ClientOptions{
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{Transport: recorder},
+	}))
+
+	filename := filepath.Join(t.TempDir(), "cassette.json")
+	if err := recorder.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret-key") {
+		t.Error("cassette contains the api key")
+	}
+	for _, want := range []string{
+		`"Authorization": [`,
+		`"REDACTED"`,
+		`"Pepperoni": 1`,
+		`"path": "/v1/votings"`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("cassette does not contain %s:\n%s", want, data)
+		}
+	}
+
+	c, err := cassette.Load(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, "interactions", len(c.Interactions), 4)
+
+	replayer := cassette.NewReplayer(c, cassette.Strict)
+	replayed := session(t, directdecisions.NewClient("other-key", &directdecisions.ClientOptions{
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{Transport: replayer},
+	}))
+
+	assertEqual(t, "replayed", replayed, recorded)
+	assertEqual(t, "unused", len(replayer.Unused()), 0)
+}
+
+func TestBody(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		body   string
+		stored string
+	}{
+		{
+			name:   "json",
+			body:   `{"id":"40f80454800b2bd7c172"}`,
+			stored: `{"json":{"id":"40f80454800b2bd7c172"}}`,
+		},
+		{
+			name:   "json with newline",
+			body:   "[\"Margarita\",\"Pepperoni\"]\n",
+			stored: `{"json":["Margarita","Pepperoni"],"newline":true}`,
+		},
+		{
+			name:   "json string",
+			body:   `"ok"`,
+			stored: `{"json":"ok"}`,
+		},
+		{
+			name:   "indented json",
+			body:   "{\n  \"id\": 1\n}",
+			stored: `{"text":"{\n  \"id\": 1\n}"}`,
+		},
+		{
+			name:   "escaped characters",
+			body:   `{"error":"<invalid>"}`,
+			stored: `{"text":"{\"error\":\"\u003cinvalid\u003e\"}"}`,
+		},
+		{
+			name:   "text",
+			body:   "ok",
+			stored: `{"text":"ok"}`,
+		},
+		{
+			name:   "binary",
+			body:   "\xff\xfe",
+			stored: `{"base64":"//4="}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(cassette.Body(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertEqual(t, "stored", string(data), tc.stored)
+
+			var got cassette.Body
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			assertEqual(t, "body", string(got), tc.body)
+		})
+	}
+}
+
+func assertEqual(t testing.TB, name string, got, want any) {
+	t.Helper()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s: got %+v, want %+v", name, got, want)
+	}
+}
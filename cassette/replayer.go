@@ -0,0 +1,146 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// ErrNoInteraction is returned by the Replayer if no recorded interaction
+// matches the request.
+var ErrNoInteraction = errors.New("cassette: no matching interaction")
+
+// Matching is the way in which the Replayer matches requests with recorded
+// interactions.
+type Matching int
+
+const (
+	// Strict matching requires requests in the recorded order with the same
+	// method, path and body, where JSON bodies are compared by value.
+	Strict Matching = iota
+	// Lenient matching requires the same method, path and body, compared
+	// as with Strict matching, in any order. Every interaction is replayed
+	// once, except the last matching one that is repeated for any further
+	// requests.
+	Lenient
+)
+
+// Replayer is an http.RoundTripper that responds to requests with recorded
+// interactions from a cassette without network access. It is safe for
+// concurrent use.
+type Replayer struct {
+	interactions []Interaction
+	matching     Matching
+
+	mu   sync.Mutex
+	used []bool
+	next int // index of the next interaction for Strict matching
+}
+
+// NewReplayer returns a new Replayer for the cassette.
+func NewReplayer(c *Cassette, m Matching) *Replayer {
+	return &Replayer{
+		interactions: c.Interactions,
+		matching:     m,
+		used:         make([]bool, len(c.Interactions)),
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	i, err := r.match(req, body)
+	if err == nil {
+		r.used[i] = true
+	}
+	r.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := r.interactions[i].Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%v %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		StatusCode:    resp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        resp.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(resp.Body)),
+		ContentLength: int64(len(resp.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Replayer) match(req *http.Request, body []byte) (int, error) {
+	path := req.URL.RequestURI()
+
+	if r.matching == Strict {
+		if r.next >= len(r.interactions) {
+			return 0, fmt.Errorf("%w: %s %s: all %v interactions are replayed", ErrNoInteraction, req.Method, path, len(r.interactions))
+		}
+		i := r.next
+		want := r.interactions[i].Request
+		if want.Method != req.Method || want.Path != path {
+			return 0, fmt.Errorf("%w: %s %s: want interaction %v %s %s", ErrNoInteraction, req.Method, path, i, want.Method, want.Path)
+		}
+		if !equalBodies(want.Body, body) {
+			return 0, fmt.Errorf("%w: %s %s: want interaction %v body %s, got %s", ErrNoInteraction, req.Method, path, i, want.Body, body)
+		}
+		r.next++
+		return i, nil
+	}
+
+	last := -1
+	for i, in := range r.interactions {
+		if in.Request.Method != req.Method || in.Request.Path != path || !equalBodies(in.Request.Body, body) {
+			continue
+		}
+		if !r.used[i] {
+			return i, nil
+		}
+		last = i
+	}
+	if last < 0 {
+		return 0, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, path)
+	}
+	return last, nil
+}
+
+// Unused returns interactions that were not replayed.
+func (r *Replayer) Unused() (interactions []Interaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, used := range r.used {
+		if !used {
+			interactions = append(interactions, r.interactions[i])
+		}
+	}
+	return interactions
+}
+
+// equalBodies compares bodies as JSON values if both are valid JSON, or byte
+// by byte otherwise.
+func equalBodies(a, b []byte) bool {
+	var av, bv any
+	if json.Unmarshal(a, &av) == nil && json.Unmarshal(b, &bv) == nil {
+		return reflect.DeepEqual(av, bv)
+	}
+	return bytes.Equal(a, b)
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cassette_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"directdecisions.com/directdecisions"
+	"directdecisions.com/directdecisions/cassette"
+)
+
+var testCassette = &cassette.Cassette{
+	Interactions: []cassette.Interaction{
+		{
+			Request: cassette.Request{
+				Method: http.MethodPost,
+				Path:   "/v1/votings",
+				Body:   cassette.Body(`{"choices": ["Margarita", "Pepperoni"]}`),
+			},
+			Response: cassette.Response{
+				StatusCode: http.StatusCreated,
+				Header:     http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+				Body:       cassette.Body(`{"id": "40f80454800b2bd7c172", "choices": ["Margarita", "Pepperoni"]}`),
+			},
+		},
+		{
+			Request: cassette.Request{
+				Method: http.MethodGet,
+				Path:   "/v1/votings/40f80454800b2bd7c172",
+			},
+			Response: cassette.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+				Body:       cassette.Body(`{"id": "40f80454800b2bd7c172", "choices": ["Margarita", "Pepperoni"]}`),
+			},
+		},
+	},
+}
+
+func newReplayClient(m cassette.Matching) (*directdecisions.Client, *cassette.Replayer) {
+	r := cassette.NewReplayer(testCassette, m)
+	return directdecisions.NewClient("", &directdecisions.ClientOptions{
+		HTTPClient: &http.Client{Transport: r},
+	}), r
+}
+
+func TestReplayer_strict(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("matching", func(t *testing.T) {
+		client, r := newReplayClient(cassette.Strict)
+
+		// JSON bodies are compared by value.
+		v, err := client.Votings.Create(ctx, []string{"Margarita", "Pepperoni"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "voting", v, &directdecisions.Voting{ID: "40f80454800b2bd7c172", Choices: []string{"Margarita", "Pepperoni"}})
+		assertEqual(t, "unused", len(r.Unused()), 1)
+
+		if _, err := client.Votings.Voting(ctx, "40f80454800b2bd7c172"); err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "unused", len(r.Unused()), 0)
+
+		_, err = client.Votings.Voting(ctx, "40f80454800b2bd7c172")
+		assertNoInteraction(t, err)
+	})
+
+	t.Run("order", func(t *testing.T) {
+		client, _ := newReplayClient(cassette.Strict)
+
+		_, err := client.Votings.Voting(ctx, "40f80454800b2bd7c172")
+		assertNoInteraction(t, err)
+	})
+
+	t.Run("body", func(t *testing.T) {
+		client, _ := newReplayClient(cassette.Strict)
+
+		_, err := client.Votings.Create(ctx, []string{"Pepperoni", "Margarita"})
+		assertNoInteraction(t, err)
+	})
+}
+
+func TestReplayer_lenient(t *testing.T) {
+	ctx := context.Background()
+	client, r := newReplayClient(cassette.Lenient)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Votings.Voting(ctx, "40f80454800b2bd7c172"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := client.Votings.Create(ctx, []string{"Capricciosa"})
+	assertNoInteraction(t, err)
+	assertEqual(t, "unused", len(r.Unused()), 1)
+
+	// JSON bodies are compared by value.
+	if _, err := client.Votings.Create(ctx, []string{"Margarita", "Pepperoni"}); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, "unused", len(r.Unused()), 0)
+
+	err = client.Votings.Delete(ctx, "40f80454800b2bd7c172")
+	assertNoInteraction(t, err)
+}
+
+func assertNoInteraction(t testing.TB, err error) {
+	t.Helper()
+
+	if !errors.Is(err, cassette.ErrNoInteraction) {
+		t.Errorf("got error %v, want %v", err, cassette.ErrNoInteraction)
+	}
+}
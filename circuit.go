@@ -0,0 +1,241 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCircuitFailureThreshold = 5
+	defaultCircuitSuccessThreshold = 1
+	defaultCircuitCoolDown         = 30 * time.Second
+)
+
+// CircuitState is the state of the Client's circuit breaker.
+type CircuitState int
+
+// Circuit breaker states.
+const (
+	// CircuitClosed is the state in which requests are sent.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen is the state in which requests fail with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen is the state in which trial requests are sent, one at
+	// a time, to decide if the circuit should be closed or opened again.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerOptions configures the circuit breaker that stops sending
+// requests when the API is failing.
+//
+// The circuit opens after FailureThreshold consecutive failed requests, where
+// a failure is a response with one of the Statuses or a transport error, but
+// not a cancellation of the request context. After the CoolDown, the circuit
+// becomes half-open and permits a single trial request at a time. It closes
+// after SuccessThreshold successful trial requests and opens again on a failed
+// one.
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failures that opens the
+	// circuit. If it is zero, 5 is used.
+	FailureThreshold int
+	// SuccessThreshold is the number of successful trial requests that
+	// closes the half-open circuit. If it is zero, 1 is used.
+	SuccessThreshold int
+	// CoolDown is the duration for which the circuit stays open. If it is
+	// zero, 30 seconds is used.
+	CoolDown time.Duration
+	// Statuses are HTTP response status codes that are counted as failures.
+	// If it is nil, Bad Gateway, Service Unavailable and Gateway Timeout
+	// statuses are failures.
+	Statuses []int
+	// OnStateChange, if not nil, is called synchronously on every state
+	// change.
+	OnStateChange func(from, to CircuitState)
+}
+
+var defaultCircuitStatuses = []int{
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// breaker implements the circuit breaker states. It is safe for concurrent use.
+type breaker struct {
+	failureThreshold int
+	successThreshold int
+	coolDown         time.Duration
+	statuses         map[int]struct{}
+	onStateChange    func(from, to CircuitState)
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int       // consecutive failures in the closed state
+	successes int       // successful trials in the half-open state
+	trials    int       // trial requests in progress
+	opened    time.Time // time when the circuit was opened
+}
+
+func newBreaker(o *CircuitBreakerOptions) *breaker {
+	if o == nil {
+		return nil
+	}
+	b := &breaker{
+		failureThreshold: o.FailureThreshold,
+		successThreshold: o.SuccessThreshold,
+		coolDown:         o.CoolDown,
+		statuses:         make(map[int]struct{}),
+		onStateChange:    o.OnStateChange,
+	}
+	if b.failureThreshold <= 0 {
+		b.failureThreshold = defaultCircuitFailureThreshold
+	}
+	if b.successThreshold <= 0 {
+		b.successThreshold = defaultCircuitSuccessThreshold
+	}
+	if b.coolDown <= 0 {
+		b.coolDown = defaultCircuitCoolDown
+	}
+	statuses := o.Statuses
+	if statuses == nil {
+		statuses = defaultCircuitStatuses
+	}
+	for _, s := range statuses {
+		b.statuses[s] = struct{}{}
+	}
+	return b
+}
+
+// allow returns ErrCircuitOpen if a request should not be sent and whether
+// the permitted request is a trial in the half-open state.
+func (b *breaker) allow(now time.Time) (trial bool, err error) {
+	if b == nil {
+		return false, nil
+	}
+
+	b.mu.Lock()
+	from := b.state
+	if b.state == CircuitOpen && !now.Before(b.opened.Add(b.coolDown)) {
+		b.state = CircuitHalfOpen
+		b.successes = 0
+	}
+	switch b.state {
+	case CircuitOpen:
+		err = ErrCircuitOpen
+	case CircuitHalfOpen:
+		if b.trials > 0 {
+			err = ErrCircuitOpen
+		} else {
+			b.trials++
+			trial = true
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return trial, err
+}
+
+// done records the result of a permitted request.
+func (b *breaker) done(trial bool, err error, now time.Time) {
+	if b == nil {
+		return
+	}
+	failure, ok := b.failure(err)
+
+	b.mu.Lock()
+	from := b.state
+	if trial {
+		b.trials--
+	}
+	switch {
+	case !ok:
+	case b.state == CircuitClosed:
+		if !failure {
+			b.failures = 0
+			break
+		}
+		b.failures++
+		if b.failures >= b.failureThreshold {
+			b.open(now)
+		}
+	case b.state == CircuitHalfOpen && trial:
+		if failure {
+			b.open(now)
+			break
+		}
+		b.successes++
+		if b.successes >= b.successThreshold {
+			b.state = CircuitClosed
+			b.failures = 0
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+func (b *breaker) open(now time.Time) {
+	b.state = CircuitOpen
+	b.opened = now
+}
+
+// failure returns whether the error of a request is a failure and false ok
+// if the request should not be counted at all.
+func (b *breaker) failure(err error) (failure, ok bool) {
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		return false, true
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false, false
+	case errors.As(err, &apiErr):
+		_, failure = b.statuses[apiErr.StatusCode]
+		return failure, true
+	}
+	return true, true
+}
+
+func (b *breaker) notify(from, to CircuitState) {
+	if from != to && b.onStateChange != nil {
+		b.onStateChange(from, to)
+	}
+}
+
+func (b *breaker) currentState() CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// CircuitState returns the current state of the Client's circuit breaker. It
+// is always CircuitClosed if the circuit breaker is not enabled.
+func (c *Client) CircuitState() CircuitState {
+	return c.breaker.currentState()
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"directdecisions.com/directdecisions"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var (
+		changes   []string
+		changesMu sync.Mutex
+	)
+	metrics := new(recordingMetrics)
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Metrics: metrics,
+		CircuitBreaker: &directdecisions.CircuitBreakerOptions{
+			FailureThreshold: 2,
+			CoolDown:         50 * time.Millisecond,
+			OnStateChange: func(from, to directdecisions.CircuitState) {
+				changesMu.Lock()
+				defer changesMu.Unlock()
+
+				changes = append(changes, from.String()+" -> "+to.String())
+			},
+		},
+	})
+
+	var requests int
+	status := http.StatusServiceUnavailable
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		newStaticHandler(`{"id":"40f80454800b2bd7c172","choices":["Margarita","Diavola","Capricciosa"]}`)(w, r)
+	}))
+
+	voting := func() error {
+		_, err := client.Votings.Voting(context.Background(), "40f80454800b2bd7c172")
+		return err
+	}
+
+	assertErrors(t, voting(), directdecisions.ErrHTTPStatusServiceUnavailable)
+	assertEqual(t, "state", client.CircuitState(), directdecisions.CircuitClosed)
+	assertErrors(t, voting(), directdecisions.ErrHTTPStatusServiceUnavailable)
+	assertEqual(t, "state", client.CircuitState(), directdecisions.CircuitOpen)
+
+	// Requests are not sent while the circuit is open.
+	assertErrors(t, voting(), directdecisions.ErrCircuitOpen)
+	assertEqual(t, "requests", requests, 2)
+	assertEqual(t, "attempts", metrics.operations[2].Attempts, 0)
+
+	// A failed trial request opens the circuit again.
+	time.Sleep(50 * time.Millisecond)
+	assertErrors(t, voting(), directdecisions.ErrHTTPStatusServiceUnavailable)
+	assertEqual(t, "state", client.CircuitState(), directdecisions.CircuitOpen)
+	assertErrors(t, voting(), directdecisions.ErrCircuitOpen)
+	assertEqual(t, "requests", requests, 3)
+
+	// A successful trial request closes the circuit.
+	time.Sleep(50 * time.Millisecond)
+	status = http.StatusOK
+	assertErrors(t, voting(), nil)
+	assertEqual(t, "state", client.CircuitState(), directdecisions.CircuitClosed)
+	assertEqual(t, "requests", requests, 4)
+
+	assertEqual(t, "changes", changes, []string{
+		"closed -> open",
+		"open -> half-open",
+		"half-open -> open",
+		"open -> half-open",
+		"half-open -> closed",
+	})
+}
+
+func TestCircuitBreaker_notFailures(t *testing.T) {
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		CircuitBreaker: &directdecisions.CircuitBreakerOptions{
+			FailureThreshold: 2,
+		},
+	})
+
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	mux.HandleFunc("/v1/votings/bd7c17240f80454800b2", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, tc := range []struct {
+		ctx      context.Context
+		votingID string
+		err      error
+	}{
+		{context.Background(), "bd7c17240f80454800b2", directdecisions.ErrHTTPStatusServiceUnavailable},
+		// Responses with statuses that are not failures reset the count.
+		{context.Background(), "40f80454800b2bd7c172", directdecisions.ErrHTTPStatusNotFound},
+		{context.Background(), "bd7c17240f80454800b2", directdecisions.ErrHTTPStatusServiceUnavailable},
+		// Canceled requests are not counted.
+		{ctx, "bd7c17240f80454800b2", context.Canceled},
+	} {
+		_, err := client.Votings.Voting(tc.ctx, tc.votingID)
+		assertErrors(t, err, tc.err)
+	}
+	assertEqual(t, "state", client.CircuitState(), directdecisions.CircuitClosed)
+}
+
+func TestCircuitBreaker_disabled(t *testing.T) {
+	client, _, _ := newClient(t, "")
+
+	assertEqual(t, "state", client.CircuitState(), directdecisions.CircuitClosed)
+}
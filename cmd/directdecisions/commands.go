@@ -0,0 +1,305 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"directdecisions.com/directdecisions"
+)
+
+type command struct {
+	name  string
+	args  string
+	usage string
+	run   func(ctx context.Context, c *directdecisions.Client, args []string, out *output) error
+}
+
+var commands = []command{
+	{
+		name:  "create",
+		args:  "<choice> [<choice>...]",
+		usage: "create a new voting with choices",
+		run:   createCmd,
+	},
+	{
+		name:  "get",
+		args:  "<voting-id>",
+		usage: "show a voting",
+		run:   getCmd,
+	},
+	{
+		name:  "set",
+		args:  "<voting-id> <choice> <index>",
+		usage: "add, move or remove (negative index) a choice in a voting",
+		run:   setCmd,
+	},
+	{
+		name:  "delete",
+		args:  "<voting-id>",
+		usage: "delete a voting",
+		run:   deleteCmd,
+	},
+	{
+		name:  "vote",
+		args:  "<voting-id> <voter-id> <choice>=<rank> [<choice>=<rank>...]",
+		usage: "submit a ballot for a voter",
+		run:   voteCmd,
+	},
+	{
+		name:  "unvote",
+		args:  "<voting-id> <voter-id>",
+		usage: "remove the ballot of a voter",
+		run:   unvoteCmd,
+	},
+	{
+		name:  "ballot",
+		args:  "<voting-id> <voter-id>",
+		usage: "show the ballot of a voter",
+		run:   ballotCmd,
+	},
+	{
+		name:  "results",
+		args:  "<voting-id>",
+		usage: "show voting results",
+		run:   resultsCmd,
+	},
+	{
+		name:  "duels",
+		args:  "<voting-id>",
+		usage: "show voting results with pairwise choice duels",
+		run:   duelsCmd,
+	},
+	{
+		name:  "rate",
+		args:  "<voting-id>",
+		usage: "get a voting and show the rate limit information from the response",
+		run:   rateCmd,
+	},
+}
+
+func createCmd(ctx context.Context, c *directdecisions.Client, args []string, out *output) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	v, err := c.Votings.Create(ctx, args)
+	if err != nil {
+		return err
+	}
+	return out.voting(v)
+}
+
+func getCmd(ctx context.Context, c *directdecisions.Client, args []string, out *output) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	v, err := c.Votings.Voting(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return out.voting(v)
+}
+
+func setCmd(ctx context.Context, c *directdecisions.Client, args []string, out *output) error {
+	if len(args) != 3 {
+		return errUsage
+	}
+	index, err := strconv.Atoi(args[2])
+	if err != nil {
+		return fmt.Errorf("%w: invalid index %q", errUsage, args[2])
+	}
+	choices, err := c.Votings.Set(ctx, args[0], args[1], index)
+	if err != nil {
+		return err
+	}
+	return out.voting(&directdecisions.Voting{
+		ID:      args[0],
+		Choices: choices,
+	})
+}
+
+func deleteCmd(ctx context.Context, c *directdecisions.Client, args []string, out *output) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	if err := c.Votings.Delete(ctx, args[0]); err != nil {
+		return err
+	}
+	return out.deleted("Voting deleted")
+}
+
+func voteCmd(ctx context.Context, c *directdecisions.Client, args []string, out *output) error {
+	if len(args) < 3 {
+		return errUsage
+	}
+	ballot := make(map[string]int, len(args)-2)
+	for _, a := range args[2:] {
+		i := strings.LastIndex(a, "=")
+		if i < 0 {
+			return fmt.Errorf("%w: invalid ballot entry %q", errUsage, a)
+		}
+		rank, err := strconv.Atoi(a[i+1:])
+		if err != nil {
+			return fmt.Errorf("%w: invalid rank in %q", errUsage, a)
+		}
+		ballot[a[:i]] = rank
+	}
+	revoted, err := c.Votings.Vote(ctx, args[0], args[1], ballot)
+	if err != nil {
+		return err
+	}
+	return out.write(struct {
+		Revoted bool `json:"revoted"`
+	}{
+		Revoted: revoted,
+	}, func(w io.Writer) {
+		if revoted {
+			fmt.Fprintln(w, "Ballot replaced")
+		} else {
+			fmt.Fprintln(w, "Ballot submitted")
+		}
+	})
+}
+
+func unvoteCmd(ctx context.Context, c *directdecisions.Client, args []string, out *output) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	if err := c.Votings.Unvote(ctx, args[0], args[1]); err != nil {
+		return err
+	}
+	return out.deleted("Ballot removed")
+}
+
+func ballotCmd(ctx context.Context, c *directdecisions.Client, args []string, out *output) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	ballot, err := c.Votings.Ballot(ctx, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	return out.write(struct {
+		Ballot map[string]int `json:"ballot"`
+	}{
+		Ballot: ballot,
+	}, func(w io.Writer) {
+		choices := make([]string, 0, len(ballot))
+		for c := range ballot {
+			choices = append(choices, c)
+		}
+		sort.Slice(choices, func(i, j int) bool {
+			if ballot[choices[i]] == ballot[choices[j]] {
+				return choices[i] < choices[j]
+			}
+			return ballot[choices[i]] < ballot[choices[j]]
+		})
+		fmt.Fprintln(w, "RANK\tCHOICE")
+		for _, c := range choices {
+			fmt.Fprintf(w, "%v\t%s\n", ballot[c], c)
+		}
+	})
+}
+
+func resultsCmd(ctx context.Context, c *directdecisions.Client, args []string, out *output) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	results, tie, err := c.Votings.Results(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return out.write(struct {
+		Results []directdecisions.Result `json:"results"`
+		Tie     bool                     `json:"tie"`
+	}{
+		Results: results,
+		Tie:     tie,
+	}, func(w io.Writer) {
+		writeResults(w, results, tie)
+	})
+}
+
+func duelsCmd(ctx context.Context, c *directdecisions.Client, args []string, out *output) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	results, duels, tie, err := c.Votings.Duels(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return out.write(struct {
+		Results []directdecisions.Result `json:"results"`
+		Duels   []directdecisions.Duel   `json:"duels"`
+		Tie     bool                     `json:"tie"`
+	}{
+		Results: results,
+		Duels:   duels,
+		Tie:     tie,
+	}, func(w io.Writer) {
+		writeResults(w, results, tie)
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "LEFT\tSTRENGTH\tRIGHT\tSTRENGTH")
+		for _, d := range duels {
+			fmt.Fprintf(w, "%s\t%v\t%s\t%v\n", d.Left.Choice, d.Left.Strength, d.Right.Choice, d.Right.Strength)
+		}
+	})
+}
+
+func rateCmd(ctx context.Context, c *directdecisions.Client, args []string, out *output) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	if _, err := c.Votings.Voting(ctx, args[0]); err != nil {
+		return err
+	}
+	r := c.Rate()
+	return out.write(struct {
+		Limit     int        `json:"limit"`
+		Remaining int        `json:"remaining"`
+		Reset     *time.Time `json:"reset,omitempty"`
+		Retry     *time.Time `json:"retry,omitempty"`
+	}{
+		Limit:     r.Limit,
+		Remaining: r.Remaining,
+		Reset:     timePtr(r.Reset),
+		Retry:     timePtr(r.Retry),
+	}, func(w io.Writer) {
+		fmt.Fprintln(w, "LIMIT\tREMAINING\tRESET\tRETRY")
+		fmt.Fprintf(w, "%v\t%v\t%s\t%s\n", r.Limit, r.Remaining, formatTime(r.Reset), formatTime(r.Retry))
+	})
+}
+
+func writeResults(w io.Writer, results []directdecisions.Result, tie bool) {
+	fmt.Fprintln(w, "CHOICE\tINDEX\tWINS\tPERCENTAGE\tSTRENGTH\tADVANTAGE")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%v\t%v\t%.2f\t%v\t%v\n", r.Choice, r.Index, r.Wins, r.Percentage, r.Strength, r.Advantage)
+	}
+	if tie {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "The voting is tied.")
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command directdecisions manages Direct Decisions votings from the terminal.
+//
+// Usage:
+//
+//	directdecisions [flags] <command> [arguments]
+//
+// The API key and the API base URL are read from the -key and -url flags, or
+// from the sources described in directdecisions.LoadConfig, such as
+// DIRECTDECISIONS_API_KEY and DIRECTDECISIONS_BASE_URL environment variables
+// or the profile from the -profile flag in the config file. Run the command
+// without arguments for the list of commands.
+//
+// Results are written as aligned tables or, with the -json flag, as JSON.
+// The command exits with one of the following status codes:
+//
+//	0  success
+//	1  unclassified error
+//	2  invalid command line usage
+//	3  unauthorized or forbidden request
+//	4  voting or ballot not found
+//	5  invalid data, choice, ballot or voter ID
+//	6  rate limit exceeded
+//	7  API unavailable or internal server error
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+
+	"directdecisions.com/directdecisions"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr, os.Getenv)
+	stop()
+	os.Exit(code)
+}
+
+// Exit codes.
+const (
+	exitOK           = 0
+	exitError        = 1
+	exitUsage        = 2
+	exitUnauthorized = 3
+	exitNotFound     = 4
+	exitInvalid      = 5
+	exitRateLimited  = 6
+	exitUnavailable  = 7
+)
+
+var errUsage = errors.New("usage")
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	fs := flag.NewFlagSet("directdecisions", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	key := fs.String("key", "", "API key, instead of DIRECTDECISIONS_API_KEY environment variable")
+	baseURL := fs.String("url", "", "API base URL, instead of DIRECTDECISIONS_BASE_URL environment variable")
+	profile := fs.String("profile", "", "config file profile, instead of DIRECTDECISIONS_PROFILE environment variable")
+	jsonOutput := fs.Bool("json", false, "write output in JSON format instead of a table")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: directdecisions [flags] <command> [arguments]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Commands:")
+		for _, c := range commands {
+			fmt.Fprintf(stderr, "  %-10s %s\n", c.name, c.usage)
+		}
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == fs.Arg(0) {
+			cmd = &commands[i]
+			break
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(stderr, "unknown command %q\n", fs.Arg(0))
+		fs.Usage()
+		return exitUsage
+	}
+
+	config, err := directdecisions.LoadConfig(&directdecisions.ConfigOptions{
+		Profile: *profile,
+		Getenv:  getenv,
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return exitUsage
+	}
+	if *key != "" {
+		config.Key = *key
+		config.KeySource = "flag -key"
+	}
+	if *baseURL != "" {
+		u, err := url.Parse(*baseURL)
+		if err != nil {
+			fmt.Fprintf(stderr, "invalid url: %v\n", err)
+			return exitUsage
+		}
+		if u.Scheme == "" || u.Host == "" {
+			fmt.Fprintf(stderr, "invalid url: %q has no scheme or host\n", *baseURL)
+			return exitUsage
+		}
+		config.BaseURL = u
+		config.BaseURLSource = "flag -url"
+	}
+	if err := config.Validate(); err != nil {
+		fmt.Fprintf(stderr, "error: %v, or with -key flag\n", err)
+		return exitUsage
+	}
+	client := directdecisions.NewClient("", config.ClientOptions())
+
+	out := &output{w: stdout, json: *jsonOutput}
+
+	if err := cmd.run(ctx, client, fs.Args()[1:], out); err != nil {
+		if errors.Is(err, errUsage) {
+			if err != errUsage {
+				fmt.Fprintln(stderr, "error:", err)
+			}
+			fmt.Fprintf(stderr, "Usage: directdecisions %s %s\n", cmd.name, cmd.args)
+			return exitUsage
+		}
+		fmt.Fprintln(stderr, "error:", err)
+		return exitCode(err)
+	}
+	return exitOK
+}
+
+// exitCode returns the process exit code for the error returned by the API
+// client.
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, directdecisions.ErrHTTPStatusUnauthorized),
+		errors.Is(err, directdecisions.ErrHTTPStatusForbidden):
+		return exitUnauthorized
+	case errors.Is(err, directdecisions.ErrHTTPStatusNotFound):
+		return exitNotFound
+	case errors.Is(err, directdecisions.ErrHTTPStatusBadRequest),
+		errors.Is(err, directdecisions.ErrInvalidData),
+		errors.Is(err, directdecisions.ErrMissingChoices),
+		errors.Is(err, directdecisions.ErrChoiceRequired),
+		errors.Is(err, directdecisions.ErrChoiceTooLong),
+		errors.Is(err, directdecisions.ErrTooManyChoices),
+		errors.Is(err, directdecisions.ErrBallotRequired),
+		errors.Is(err, directdecisions.ErrVoterIDTooLong),
+		errors.Is(err, directdecisions.ErrInvalidVoterID):
+		return exitInvalid
+	case errors.Is(err, directdecisions.ErrHTTPStatusTooManyRequests):
+		return exitRateLimited
+	case errors.Is(err, directdecisions.ErrHTTPStatusInternalServerError),
+		errors.Is(err, directdecisions.ErrHTTPStatusBadGateway),
+		errors.Is(err, directdecisions.ErrHTTPStatusServiceUnavailable):
+		return exitUnavailable
+	}
+	return exitError
+}
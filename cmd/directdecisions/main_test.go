@@ -0,0 +1,136 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"directdecisions.com/directdecisions/directdecisionstest"
+)
+
+func TestRun(t *testing.T) {
+	server := directdecisionstest.NewServer(&directdecisionstest.Options{
+		Key:       "my-key",
+		RateLimit: 100,
+	})
+	t.Cleanup(server.Close)
+
+	env := map[string]string{
+		"DIRECTDECISIONS_API_KEY":  "my-key",
+		"DIRECTDECISIONS_BASE_URL": server.URL,
+	}
+
+	run := func(t *testing.T, wantCode int, args ...string) string {
+		t.Helper()
+
+		var stdout, stderr bytes.Buffer
+		code := run(context.Background(), args, &stdout, &stderr, func(key string) string {
+			return env[key]
+		})
+		if code != wantCode {
+			t.Fatalf("%v: got exit code %v, want %v: %s", args, code, wantCode, stderr.String())
+		}
+		return stdout.String()
+	}
+
+	var v struct {
+		ID      string   `json:"id"`
+		Choices []string `json:"choices"`
+	}
+	if err := json.Unmarshal([]byte(run(t, exitOK, "-json", "create", "Margarita", "Pepperoni")), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.ID == "" {
+		t.Fatal("got empty voting id")
+	}
+
+	assertContains(t, run(t, exitOK, "get", v.ID), "Voting "+v.ID, "Margarita", "Pepperoni")
+	assertContains(t, run(t, exitOK, "set", v.ID, "Capricciosa", "0"), "0  Capricciosa")
+	assertContains(t, run(t, exitOK, "vote", v.ID, "leonardo", "Pepperoni=1", "Margarita=2"), "Ballot submitted")
+	assertContains(t, run(t, exitOK, "vote", v.ID, "leonardo", "Pepperoni=1"), "Ballot replaced")
+	assertContains(t, run(t, exitOK, "ballot", v.ID, "leonardo"), "1     Pepperoni")
+	assertContains(t, run(t, exitOK, "results", v.ID), "Pepperoni  2      2     100.00")
+	assertContains(t, run(t, exitOK, "-json", "duels", v.ID), `"duels"`, `"tie": false`)
+	assertContains(t, run(t, exitOK, "rate", v.ID), "LIMIT  REMAINING", "100")
+	assertContains(t, run(t, exitOK, "-json", "unvote", v.ID, "leonardo"), `"deleted": true`)
+	run(t, exitNotFound, "ballot", v.ID, "leonardo")
+	run(t, exitInvalid, "vote", v.ID, "leonardo", "Diavola=1")
+	assertContains(t, run(t, exitOK, "delete", v.ID), "Voting deleted")
+	run(t, exitNotFound, "get", v.ID)
+
+	run(t, exitUsage)
+	run(t, exitUsage, "unknown")
+	run(t, exitUsage, "get")
+	run(t, exitUsage, "vote", v.ID, "leonardo", "Pepperoni")
+	run(t, exitUnauthorized, "-key", "other-key", "get", v.ID)
+	run(t, exitUsage, "-url", "api.example.com", "get", v.ID)
+}
+
+func TestRun_config(t *testing.T) {
+	server := directdecisionstest.NewServer(&directdecisionstest.Options{
+		Key: "my-key",
+	})
+	t.Cleanup(server.Close)
+
+	configFile := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(configFile, []byte("[work]\nkey = my-key\nbase_url = "+server.URL+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		"DIRECTDECISIONS_CONFIG_FILE": configFile,
+	}
+
+	for _, tc := range []struct {
+		args   []string
+		code   int
+		stderr string
+	}{
+		{
+			args: []string{"-profile", "work", "create", "Margarita", "Pepperoni"},
+			code: exitOK,
+		},
+		{
+			args:   []string{"-profile", "home", "create", "Margarita", "Pepperoni"},
+			code:   exitUsage,
+			stderr: `profile "home" not found`,
+		},
+		{
+			args:   []string{"create", "Margarita", "Pepperoni"},
+			code:   exitUsage,
+			stderr: `error: missing api key: it is not set in environment variables DIRECTDECISIONS_API_KEY and DIRECTDECISIONS_API_KEY_FILE, and key or key_file in profile "default" of config file ` + configFile + `, or with -key flag`,
+		},
+	} {
+		var stdout, stderr bytes.Buffer
+		code := run(context.Background(), tc.args, &stdout, &stderr, func(key string) string {
+			return env[key]
+		})
+		if code != tc.code {
+			t.Errorf("%v: got exit code %v, want %v: %s", tc.args, code, tc.code, stderr.String())
+		}
+		if !strings.Contains(stderr.String(), tc.stderr) {
+			t.Errorf("%v: got error output %q, want %q", tc.args, stderr.String(), tc.stderr)
+		}
+	}
+}
+
+// assertContains checks if the output contains all substrings, ignoring the
+// table column alignment.
+func assertContains(t *testing.T, s string, substrs ...string) {
+	t.Helper()
+
+	s = strings.Join(strings.Fields(s), " ")
+	for _, substr := range substrs {
+		if !strings.Contains(s, strings.Join(strings.Fields(substr), " ")) {
+			t.Errorf("output %q does not contain %q", s, substr)
+		}
+	}
+}
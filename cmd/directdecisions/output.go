@@ -0,0 +1,57 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"directdecisions.com/directdecisions"
+)
+
+// output writes command results either as JSON or as aligned tables.
+type output struct {
+	w    io.Writer
+	json bool
+}
+
+// write encodes v as JSON if the JSON output is enabled or calls the table
+// function with a writer that aligns tab separated columns.
+func (o *output) write(v any, table func(w io.Writer)) error {
+	if o.json {
+		enc := json.NewEncoder(o.w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(v)
+	}
+	tw := tabwriter.NewWriter(o.w, 0, 4, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+func (o *output) voting(v *directdecisions.Voting) error {
+	return o.write(v, func(w io.Writer) {
+		fmt.Fprintf(w, "Voting %s\n\n", v.ID)
+		fmt.Fprintln(w, "INDEX\tCHOICE")
+		for i, c := range v.Choices {
+			fmt.Fprintf(w, "%v\t%s\n", i, c)
+		}
+	})
+}
+
+// deleted writes the confirmation of a removed voting or ballot, so that
+// scripts get a JSON object for every successful command.
+func (o *output) deleted(message string) error {
+	return o.write(struct {
+		Deleted bool `json:"deleted"`
+	}{
+		Deleted: true,
+	}, func(w io.Writer) {
+		fmt.Fprintln(w, message)
+	})
+}
@@ -0,0 +1,346 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environment variables that are read by LoadConfig.
+const (
+	EnvAPIKey     = "DIRECTDECISIONS_API_KEY"
+	EnvAPIKeyFile = "DIRECTDECISIONS_API_KEY_FILE"
+	EnvBaseURL    = "DIRECTDECISIONS_BASE_URL"
+	EnvProfile    = "DIRECTDECISIONS_PROFILE"
+	EnvConfigFile = "DIRECTDECISIONS_CONFIG_FILE"
+)
+
+const defaultProfile = "default"
+
+// Config holds the API key and the base URL loaded by LoadConfig with the
+// descriptions of their sources, such as "environment variable
+// DIRECTDECISIONS_API_KEY".
+type Config struct {
+	Key           string   // API key, empty if it is not found.
+	KeySource     string   // Source of the API key.
+	BaseURL       *url.URL // API base URL, nil if it is not found.
+	BaseURLSource string   // Source of the API base URL.
+	Profile       string   // Name of the config file profile.
+	ConfigFile    string   // Path of the config file, even if it does not exist.
+}
+
+// ConfigOptions holds optional parameters for LoadConfig.
+type ConfigOptions struct {
+	// Profile is the name of the profile in the config file. If it is
+	// empty, the value of DIRECTDECISIONS_PROFILE environment variable or
+	// "default" is used. The config file must contain the profile only if
+	// it is set explicitly.
+	Profile string
+	// ConfigFile is the path of the config file. If it is empty, the value
+	// of DIRECTDECISIONS_CONFIG_FILE environment variable or the
+	// "directdecisions/config" file in the user configuration directory is
+	// used. The config file must exist only if it is set explicitly.
+	ConfigFile string
+	// Getenv returns the values of environment variables. If it is nil,
+	// os.Getenv is used.
+	Getenv func(key string) string
+}
+
+// LoadConfig loads the API key and the base URL together from the first
+// source that provides the API key, in the following order:
+//
+//   - DIRECTDECISIONS_API_KEY or the file with the API key from
+//     DIRECTDECISIONS_API_KEY_FILE environment variable, with the base URL
+//     from DIRECTDECISIONS_BASE_URL environment variable,
+//   - the profile in the config file, with its key or key_file and base_url.
+//
+// The config file contains named profiles with key, key_file and base_url
+// values, where relative key_file paths are relative to the config file
+// directory:
+//
+//	# Comments start with a hash.
+//	[default]
+//	key = my-api-key
+//
+//	[staging]
+//	key_file = staging.key
+//	base_url = https://staging.example.com/
+//
+// The API key is never sent to a base URL from a different source. An error
+// is returned if a profile is selected explicitly while the environment
+// variables with the API key or the base URL are set, or if the base URL is
+// set only in the environment and the API key only in the profile. The base
+// URL must be absolute, with a scheme and a host.
+//
+// Values that are not found are left empty and it is not an error.
+func LoadConfig(o *ConfigOptions) (*Config, error) {
+	if o == nil {
+		o = new(ConfigOptions)
+	}
+	getenv := o.Getenv
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+
+	c := new(Config)
+
+	c.Profile = o.Profile
+	if c.Profile == "" {
+		c.Profile = getenv(EnvProfile)
+	}
+	explicitProfile := c.Profile != ""
+	if !explicitProfile {
+		c.Profile = defaultProfile
+	}
+
+	if explicitProfile {
+		for _, name := range []string{EnvAPIKey, EnvAPIKeyFile, EnvBaseURL} {
+			if getenv(name) != "" {
+				return nil, fmt.Errorf("profile %q is selected and environment variable %s is set: api key and base url must be from the same source", c.Profile, name)
+			}
+		}
+	}
+
+	if key := getenv(EnvAPIKey); key != "" {
+		c.Key = key
+		c.KeySource = "environment variable " + EnvAPIKey
+	} else if filename := getenv(EnvAPIKeyFile); filename != "" {
+		key, err := readKeyFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("api key file from environment variable %s: %w", EnvAPIKeyFile, err)
+		}
+		c.Key = key
+		c.KeySource = "file " + filename
+	}
+
+	if v := getenv(EnvBaseURL); v != "" {
+		source := "environment variable " + EnvBaseURL
+		u, err := parseBaseURL(v, source)
+		if err != nil {
+			return nil, err
+		}
+		c.BaseURL = u
+		c.BaseURLSource = source
+	}
+
+	c.ConfigFile = o.ConfigFile
+	if c.ConfigFile == "" {
+		c.ConfigFile = getenv(EnvConfigFile)
+	}
+	explicitConfigFile := c.ConfigFile != ""
+	if !explicitConfigFile {
+		dir, err := os.UserConfigDir()
+		if err != nil && !explicitProfile {
+			// There is no default config file.
+			return c, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", c.Profile, err)
+		}
+		c.ConfigFile = filepath.Join(dir, "directdecisions", "config")
+	}
+
+	if c.Key != "" {
+		// The environment provides the key, with or without the base URL.
+		return c, nil
+	}
+
+	profiles, err := readConfigFile(c.ConfigFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && !explicitProfile && !explicitConfigFile {
+			return c, nil
+		}
+		return nil, fmt.Errorf("config file %s: %w", c.ConfigFile, err)
+	}
+	p, ok := profiles[c.Profile]
+	if !ok {
+		if explicitProfile {
+			return nil, fmt.Errorf("config file %s: profile %q not found", c.ConfigFile, c.Profile)
+		}
+		return c, nil
+	}
+
+	source := fmt.Sprintf("profile %q in config file %s", c.Profile, c.ConfigFile)
+	if c.BaseURL != nil {
+		// The environment provides only the base URL, which must not be
+		// used with the key from the profile.
+		if p["key"] != "" || p["key_file"] != "" {
+			return nil, fmt.Errorf("api key from %s and base url from %s: api key and base url must be from the same source", source, c.BaseURLSource)
+		}
+		return c, nil
+	}
+
+	if key := p["key"]; key != "" {
+		c.Key = key
+		c.KeySource = source
+	} else if filename := p["key_file"]; filename != "" {
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(filepath.Dir(c.ConfigFile), filename)
+		}
+		key, err := readKeyFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("api key file from %s: %w", source, err)
+		}
+		c.Key = key
+		c.KeySource = "file " + filename + " from " + source
+	}
+	if v := p["base_url"]; v != "" {
+		u, err := parseBaseURL(v, source)
+		if err != nil {
+			return nil, err
+		}
+		c.BaseURL = u
+		c.BaseURLSource = source
+	}
+	return c, nil
+}
+
+// ClientOptions returns new ClientOptions with the API key and the base URL
+// from the Config.
+func (c *Config) ClientOptions() *ClientOptions {
+	o := &ClientOptions{
+		BaseURL: c.BaseURL,
+	}
+	if c.Key != "" {
+		o.Credentials = staticKey(c.Key)
+	}
+	return o
+}
+
+// NewClientFromEnv constructs a new Client with the API key and the base URL
+// loaded by LoadConfig with default options. The key is not required if
+// ClientOptions Credentials are provided, and the loaded base URL is not used
+// if ClientOptions BaseURL is provided.
+//
+// Error ErrMissingKey is returned, with the description of the checked
+// sources, if the API key is not found.
+func NewClientFromEnv(o *ClientOptions) (*Client, error) {
+	var opts ClientOptions
+	if o != nil {
+		opts = *o
+	}
+
+	c, err := LoadConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Credentials == nil {
+		if err := c.Validate(); err != nil {
+			return nil, err
+		}
+		opts.Credentials = staticKey(c.Key)
+	}
+	if opts.BaseURL == nil {
+		opts.BaseURL = c.BaseURL
+	}
+	return NewClient("", &opts), nil
+}
+
+// Validate returns ErrMissingKey with the description of the checked sources
+// if the API key is not found.
+func (c *Config) Validate() error {
+	if c.Key != "" {
+		return nil
+	}
+	sources := fmt.Sprintf("environment variables %s and %s", EnvAPIKey, EnvAPIKeyFile)
+	if c.ConfigFile != "" {
+		sources += fmt.Sprintf(", and key or key_file in profile %q of config file %s", c.Profile, c.ConfigFile)
+	}
+	return fmt.Errorf("%w: it is not set in %s", ErrMissingKey, sources)
+}
+
+// parseBaseURL returns the absolute base URL from the described source.
+func parseBaseURL(v, source string) (*url.URL, error) {
+	u, err := url.Parse(v)
+	if err != nil {
+		return nil, fmt.Errorf("base url from %s: %w", source, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("base url from %s: %q has no scheme or host", source, v)
+	}
+	return u, nil
+}
+
+// readKeyFile returns the API key from the file without surrounding spaces.
+func readKeyFile(filename string) (string, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	key := strings.TrimSpace(string(b))
+	if key == "" {
+		return "", fmt.Errorf("file %s is empty", filename)
+	}
+	return key, nil
+}
+
+func readConfigFile(filename string) (map[string]map[string]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseConfig(f)
+}
+
+// configKeys are the valid keys in config file profiles.
+var configKeys = map[string]struct{}{
+	"key":      {},
+	"key_file": {},
+	"base_url": {},
+}
+
+// parseConfig returns values from the config file by profile names.
+func parseConfig(r io.Reader) (map[string]map[string]string, error) {
+	profiles := make(map[string]map[string]string)
+	var profile map[string]string
+
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if strings.HasPrefix(text, "[") {
+			name, ok := strings.CutSuffix(text[1:], "]")
+			name = strings.TrimSpace(name)
+			if !ok || name == "" {
+				return nil, fmt.Errorf("line %v: invalid profile %s", line, text)
+			}
+			if _, ok := profiles[name]; ok {
+				return nil, fmt.Errorf("line %v: duplicate profile %q", line, name)
+			}
+			profile = make(map[string]string)
+			profiles[name] = profile
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %v: expected key = value", line)
+		}
+		key = strings.TrimSpace(key)
+		if _, ok := configKeys[key]; !ok {
+			return nil, fmt.Errorf("line %v: unknown key %q", line, key)
+		}
+		if profile == nil {
+			return nil, fmt.Errorf("line %v: key %q outside of a profile", line, key)
+		}
+		profile[key] = strings.TrimSpace(value)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
@@ -0,0 +1,348 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"directdecisions.com/directdecisions"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	configFile := writeFile(t, dir, "config", `
+# Comments start with a hash.
+[default]
+key = default-key
+base_url = https://default.example.com/
+
+[staging]
+key_file = staging.key
+base_url = https://staging.example.com/
+
+[partial]
+base_url = https://partial.example.com/
+`)
+	writeFile(t, dir, "staging.key", "staging-key\n")
+	envKeyFile := writeFile(t, dir, "env.key", " env-file-key \n")
+
+	for _, tc := range []struct {
+		name    string
+		env     map[string]string
+		options directdecisions.ConfigOptions
+		want    directdecisions.Config
+	}{
+		{
+			name: "environment",
+			env: map[string]string{
+				directdecisions.EnvAPIKey:     "env-key",
+				directdecisions.EnvAPIKeyFile: envKeyFile,
+				directdecisions.EnvBaseURL:    "https://env.example.com/",
+			},
+			want: directdecisions.Config{
+				Key:           "env-key",
+				KeySource:     "environment variable DIRECTDECISIONS_API_KEY",
+				BaseURL:       mustParseURL(t, "https://env.example.com/"),
+				BaseURLSource: "environment variable DIRECTDECISIONS_BASE_URL",
+				Profile:       "default",
+				ConfigFile:    configFile,
+			},
+		},
+		{
+			name: "key file",
+			env: map[string]string{
+				directdecisions.EnvAPIKeyFile: envKeyFile,
+			},
+			want: directdecisions.Config{
+				Key:        "env-file-key",
+				KeySource:  "file " + envKeyFile,
+				Profile:    "default",
+				ConfigFile: configFile,
+			},
+		},
+		{
+			name: "default profile",
+			want: directdecisions.Config{
+				Key:           "default-key",
+				KeySource:     `profile "default" in config file ` + configFile,
+				BaseURL:       mustParseURL(t, "https://default.example.com/"),
+				BaseURLSource: `profile "default" in config file ` + configFile,
+				Profile:       "default",
+				ConfigFile:    configFile,
+			},
+		},
+		{
+			name: "profile from environment",
+			env: map[string]string{
+				directdecisions.EnvProfile: "staging",
+			},
+			want: directdecisions.Config{
+				Key:           "staging-key",
+				KeySource:     "file " + filepath.Join(dir, "staging.key") + ` from profile "staging" in config file ` + configFile,
+				BaseURL:       mustParseURL(t, "https://staging.example.com/"),
+				BaseURLSource: `profile "staging" in config file ` + configFile,
+				Profile:       "staging",
+				ConfigFile:    configFile,
+			},
+		},
+		{
+			name: "environment overrides profile",
+			env: map[string]string{
+				directdecisions.EnvAPIKey: "env-key",
+			},
+			want: directdecisions.Config{
+				Key:        "env-key",
+				KeySource:  "environment variable DIRECTDECISIONS_API_KEY",
+				Profile:    "default",
+				ConfigFile: configFile,
+			},
+		},
+		{
+			name: "missing key",
+			options: directdecisions.ConfigOptions{
+				Profile: "partial",
+			},
+			want: directdecisions.Config{
+				BaseURL:       mustParseURL(t, "https://partial.example.com/"),
+				BaseURLSource: `profile "partial" in config file ` + configFile,
+				Profile:       "partial",
+				ConfigFile:    configFile,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			env := map[string]string{
+				directdecisions.EnvConfigFile: configFile,
+			}
+			for k, v := range tc.env {
+				env[k] = v
+			}
+			tc.options.Getenv = func(key string) string {
+				return env[key]
+			}
+
+			got, err := directdecisions.LoadConfig(&tc.options)
+			assertErrors(t, err, nil)
+			assertEqual(t, "config", *got, tc.want)
+		})
+	}
+}
+
+func TestLoadConfig_defaultConfigFile(t *testing.T) {
+	// os.UserConfigDir reads the process environment.
+	home := t.TempDir()
+	for _, k := range []string{"HOME", "XDG_CONFIG_HOME", "AppData"} {
+		t.Setenv(k, home)
+	}
+	dir, err := os.UserConfigDir()
+	assertErrors(t, err, nil)
+
+	// The default config file is not required.
+	got, err := directdecisions.LoadConfig(&directdecisions.ConfigOptions{
+		Getenv: func(string) string { return "" },
+	})
+	assertErrors(t, err, nil)
+	assertEqual(t, "config", *got, directdecisions.Config{
+		Profile:    "default",
+		ConfigFile: filepath.Join(dir, "directdecisions", "config"),
+	})
+
+	if err := os.MkdirAll(filepath.Join(dir, "directdecisions"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "directdecisions"), "config", "[default]\nkey = default-key\n")
+	got, err = directdecisions.LoadConfig(&directdecisions.ConfigOptions{
+		Getenv: func(string) string { return "" },
+	})
+	assertErrors(t, err, nil)
+	assertEqual(t, "key", got.Key, "default-key")
+}
+
+func TestLoadConfig_errors(t *testing.T) {
+	dir := t.TempDir()
+	configFile := writeFile(t, dir, "config", "[default]\nkey_file = missing.key\n")
+	writeFile(t, dir, "empty.key", "\n")
+
+	for _, tc := range []struct {
+		name    string
+		config  string
+		env     map[string]string
+		options directdecisions.ConfigOptions
+		err     string
+	}{
+		{
+			name: "missing profile",
+			options: directdecisions.ConfigOptions{
+				ConfigFile: configFile,
+				Profile:    "production",
+			},
+			err: "config file " + configFile + `: profile "production" not found`,
+		},
+		{
+			name: "missing config file",
+			options: directdecisions.ConfigOptions{
+				ConfigFile: filepath.Join(dir, "missing"),
+			},
+			err: "config file " + filepath.Join(dir, "missing") + ":",
+		},
+		{
+			name: "missing profile key file",
+			options: directdecisions.ConfigOptions{
+				ConfigFile: configFile,
+			},
+			err: `api key file from profile "default" in config file ` + configFile + ":",
+		},
+		{
+			name: "empty key file",
+			env: map[string]string{
+				directdecisions.EnvAPIKeyFile: filepath.Join(dir, "empty.key"),
+			},
+			err: "api key file from environment variable DIRECTDECISIONS_API_KEY_FILE: file " + filepath.Join(dir, "empty.key") + " is empty",
+		},
+		{
+			name: "invalid base url",
+			env: map[string]string{
+				directdecisions.EnvBaseURL: "://",
+			},
+			err: "base url from environment variable DIRECTDECISIONS_BASE_URL:",
+		},
+		{
+			name: "base url without scheme",
+			env: map[string]string{
+				directdecisions.EnvBaseURL: "api.example.com",
+			},
+			err: `base url from environment variable DIRECTDECISIONS_BASE_URL: "api.example.com" has no scheme or host`,
+		},
+		{
+			name:   "profile base url without scheme",
+			config: "[default]\nkey = my-key\nbase_url = api.example.com\n",
+			err:    `base url from profile "default" in config file`,
+		},
+		{
+			name: "environment key with selected profile",
+			env: map[string]string{
+				directdecisions.EnvAPIKey:  "env-key",
+				directdecisions.EnvProfile: "default",
+			},
+			options: directdecisions.ConfigOptions{
+				ConfigFile: configFile,
+				Profile:    "staging",
+			},
+			err: `profile "staging" is selected and environment variable DIRECTDECISIONS_API_KEY is set`,
+		},
+		{
+			name: "environment base url with profile key",
+			env: map[string]string{
+				directdecisions.EnvBaseURL: "https://env.example.com/",
+			},
+			options: directdecisions.ConfigOptions{
+				ConfigFile: configFile,
+			},
+			err: `api key from profile "default" in config file ` + configFile + " and base url from environment variable DIRECTDECISIONS_BASE_URL",
+		},
+		{
+			name:   "invalid profile",
+			config: "[default\nkey = my-key\n",
+			err:    "line 1: invalid profile [default",
+		},
+		{
+			name:   "duplicate profile",
+			config: "[default]\n[default]\n",
+			err:    `line 2: duplicate profile "default"`,
+		},
+		{
+			name:   "invalid line",
+			config: "[default]\n\nkey\n",
+			err:    "line 3: expected key = value",
+		},
+		{
+			name:   "unknown key",
+			config: "[default]\nurl = https://example.com/\n",
+			err:    `line 2: unknown key "url"`,
+		},
+		{
+			name:   "key outside of profile",
+			config: "key = my-key\n",
+			err:    `line 1: key "key" outside of a profile`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.config != "" {
+				tc.options.ConfigFile = writeFile(t, t.TempDir(), "config", tc.config)
+			}
+			tc.options.Getenv = func(key string) string {
+				return tc.env[key]
+			}
+
+			_, err := directdecisions.LoadConfig(&tc.options)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("got error %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	_, mux, baseURL := newClient(t, "")
+
+	var authorization string
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("DELETE", func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+
+	configFile := writeFile(t, t.TempDir(), "config", "")
+	t.Setenv(directdecisions.EnvAPIKey, "")
+	t.Setenv(directdecisions.EnvAPIKeyFile, "")
+	t.Setenv(directdecisions.EnvProfile, "")
+	t.Setenv(directdecisions.EnvConfigFile, configFile)
+	t.Setenv(directdecisions.EnvBaseURL, baseURL.String())
+
+	_, err := directdecisions.NewClientFromEnv(nil)
+	assertErrors(t, err, directdecisions.ErrMissingKey)
+	assertEqual(t, "error", err.Error(), `missing api key: it is not set in environment variables DIRECTDECISIONS_API_KEY and DIRECTDECISIONS_API_KEY_FILE, and key or key_file in profile "default" of config file `+configFile)
+
+	// Credentials do not require the key.
+	client, err := directdecisions.NewClientFromEnv(&directdecisions.ClientOptions{
+		Credentials: directdecisions.NewKeyPool("pool-key"),
+	})
+	assertErrors(t, err, nil)
+	assertErrors(t, client.Votings.Delete(context.Background(), "40f80454800b2bd7c172"), nil)
+	assertEqual(t, "authorization", authorization, "Bearer pool-key")
+
+	// The key from the profile is used only with the base URL from the profile.
+	t.Setenv(directdecisions.EnvBaseURL, "")
+	writeFile(t, filepath.Dir(configFile), "config", "[default]\nkey = config-key\nbase_url = "+baseURL.String()+"\n")
+	client, err = directdecisions.NewClientFromEnv(nil)
+	assertErrors(t, err, nil)
+	assertErrors(t, client.Votings.Delete(context.Background(), "40f80454800b2bd7c172"), nil)
+	assertEqual(t, "authorization", authorization, "Bearer config-key")
+}
+
+func writeFile(t testing.TB, dir, name, content string) string {
+	t.Helper()
+
+	filename := filepath.Join(dir, name)
+	if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func mustParseURL(t testing.TB, s string) *url.URL {
+	t.Helper()
+
+	u, err := url.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credentials provides API keys that authenticate requests. Implementations
+// must be safe for concurrent use.
+type Credentials interface {
+	// Key returns the API key for the next request. It is called before
+	// every attempt that sends a request, so that keys can be loaded lazily
+	// and rotated at any time.
+	Key(ctx context.Context) (key string, err error)
+}
+
+// CredentialsReporter is implemented by Credentials that track results of
+// requests authenticated with their keys.
+type CredentialsReporter interface {
+	Credentials
+	// Report is called after every sent request with the key that
+	// authenticated it, the rate limit information from the response and
+	// the request error.
+	Report(key string, rate Rate, err error)
+}
+
+// CredentialsFunc type is an adapter to allow the use of ordinary functions as
+// Credentials.
+type CredentialsFunc func(ctx context.Context) (key string, err error)
+
+// Key calls f(ctx).
+func (f CredentialsFunc) Key(ctx context.Context) (key string, err error) {
+	return f(ctx)
+}
+
+// staticKey is Credentials with a single key that never changes.
+type staticKey string
+
+func (k staticKey) Key(context.Context) (string, error) {
+	return string(k), nil
+}
+
+// identifiedCredentials is implemented by Credentials that can be identified
+// without resolving the key for the next request.
+type identifiedCredentials interface {
+	id() string
+}
+
+func (k staticKey) id() string {
+	return string(k)
+}
+
+// credentialsID returns the string that identifies the Client's credentials
+// and whether they can be identified without resolving the key.
+func (c *Client) credentialsID() (id string, ok bool) {
+	switch cr := c.credentials.(type) {
+	case nil:
+		return "", true
+	case identifiedCredentials:
+		return cr.id(), true
+	}
+	return "", false
+}
+
+// key returns the key for the next request from the Client's credentials.
+func (c *Client) key(ctx context.Context) (string, error) {
+	if c.credentials == nil {
+		return "", nil
+	}
+	return c.credentials.Key(ctx)
+}
+
+// reportKey passes the result of the request to the Client's credentials.
+func (c *Client) reportKey(key string, rate Rate, err error) {
+	if r, ok := c.credentials.(CredentialsReporter); ok {
+		r.Report(key, rate, err)
+	}
+}
+
+// setAuthorization sets the authentication header with the key to the request.
+func setAuthorization(r *http.Request, key string) {
+	if key == "" {
+		return
+	}
+	r.Header.Set("Authorization", "Bearer "+key)
+}
+
+// KeyPool is Credentials with multiple API keys that spreads requests across
+// them by the rate limit information of every key. It returns the key with
+// the most remaining requests in the current rate limit window, taking keys in
+// turns when they are equal, and it stops returning keys that are rejected
+// with ErrHTTPStatusUnauthorized while other keys are available, so that the
+// Client fails over to them.
+//
+// The Client's rate limiter tracks the rate limit of every key separately,
+// while the Client's Rate method returns the rate limit information of the
+// most recently used key.
+type KeyPool struct {
+	mu   sync.Mutex
+	keys []*poolKey
+	next int // index to start the search for the next key from
+}
+
+type poolKey struct {
+	key          string
+	rate         Rate
+	unauthorized bool
+}
+
+// NewKeyPool returns a new KeyPool with the keys.
+func NewKeyPool(keys ...string) *KeyPool {
+	p := new(KeyPool)
+	p.SetKeys(keys...)
+	return p
+}
+
+// SetKeys replaces keys in the pool. The state of keys that are already in the
+// pool is preserved.
+func (p *KeyPool) SetKeys(keys ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	current := make(map[string]*poolKey, len(p.keys))
+	for _, k := range p.keys {
+		current[k.key] = k
+	}
+	p.keys = make([]*poolKey, 0, len(keys))
+	for _, key := range keys {
+		k, ok := current[key]
+		if !ok {
+			k = &poolKey{key: key}
+		}
+		p.keys = append(p.keys, k)
+	}
+	p.next = 0
+}
+
+// Key returns the key for the next request or ErrNoKeys if the pool is empty.
+func (p *KeyPool) Key(context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n := len(p.keys)
+	if n == 0 {
+		return "", ErrNoKeys
+	}
+	now := time.Now()
+	best, bestRemaining := -1, 0
+	for i := 0; i < n; i++ {
+		j := (p.next + i) % n
+		k := p.keys[j]
+		if k.unauthorized {
+			continue
+		}
+		if r := k.remaining(now); best < 0 || r > bestRemaining {
+			best, bestRemaining = j, r
+		}
+	}
+	if best < 0 {
+		// All keys are rejected, try them in turns.
+		best = p.next % n
+	}
+	p.next = best + 1
+	return p.keys[best].key, nil
+}
+
+// Report records the rate limit information of the key and marks it as
+// unauthorized if the request is rejected with ErrHTTPStatusUnauthorized.
+func (p *KeyPool) Report(key string, rate Rate, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, k := range p.keys {
+		if k.key != key {
+			continue
+		}
+		if rate.Limit != 0 || !rate.Retry.IsZero() {
+			k.rate = rate
+		}
+		var apiErr *APIError
+		switch {
+		case errors.Is(err, ErrHTTPStatusUnauthorized):
+			k.unauthorized = true
+		case err == nil, errors.As(err, &apiErr):
+			k.unauthorized = false
+		}
+		return
+	}
+}
+
+// Rate returns the rate limit information from the most recent response to a
+// request authenticated with the key.
+func (p *KeyPool) Rate(key string) Rate {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, k := range p.keys {
+		if k.key == key {
+			return k.rate
+		}
+	}
+	return Rate{}
+}
+
+// id returns all keys in the pool, so that the pool is identified by them.
+func (p *KeyPool) id() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	keys := make([]string, 0, len(p.keys))
+	for _, k := range p.keys {
+		keys = append(keys, k.key)
+	}
+	return strings.Join(keys, "\n")
+}
+
+// remaining returns the number of requests that can be sent with the key in
+// the current rate limit window.
+func (k *poolKey) remaining(now time.Time) int {
+	if now.Before(k.rate.Retry) {
+		return 0
+	}
+	if k.rate.Limit == 0 || now.After(k.rate.Reset) {
+		// The limit is not known or the window has been reset.
+		return math.MaxInt
+	}
+	return k.rate.Remaining
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"directdecisions.com/directdecisions"
+)
+
+func TestKeyPool_failover(t *testing.T) {
+	pool := directdecisions.NewKeyPool("key-1", "key-2")
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Credentials: pool,
+	})
+
+	var keys []string
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") != "Bearer key-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		newStaticHandler(`{"id":"40f80454800b2bd7c172","choices":["Margarita","Diavola","Capricciosa"]}`)(w, r)
+	}))
+
+	for i := 0; i < 2; i++ {
+		got, err := client.Votings.Voting(context.Background(), "40f80454800b2bd7c172")
+		assertErrors(t, err, nil)
+		assertEqual(t, "voting", got, votingsServiceVotingWant)
+	}
+
+	// The rejected key is not used again.
+	assertEqual(t, "keys", keys, []string{"Bearer key-1", "Bearer key-2", "Bearer key-2"})
+
+	// Every key is tried once when all of them are rejected, starting from
+	// the one that is not known to be rejected.
+	pool.SetKeys("key-1", "key-3")
+	keys = nil
+	_, err := client.Votings.Voting(context.Background(), "40f80454800b2bd7c172")
+	assertErrors(t, err, directdecisions.ErrHTTPStatusUnauthorized)
+	assertEqual(t, "keys", keys, []string{"Bearer key-3", "Bearer key-1"})
+}
+
+func TestKeyPool_Key(t *testing.T) {
+	pool := directdecisions.NewKeyPool("key-1", "key-2", "key-3")
+
+	key := func() string {
+		t.Helper()
+
+		key, err := pool.Key(context.Background())
+		assertErrors(t, err, nil)
+		return key
+	}
+
+	// Keys without rate limit information are taken in turns.
+	for _, want := range []string{"key-1", "key-2", "key-3", "key-1"} {
+		assertEqual(t, "key", key(), want)
+	}
+
+	reset := time.Now().Add(time.Minute)
+	pool.Report("key-1", directdecisions.Rate{Limit: 100, Remaining: 10, Reset: reset}, nil)
+	pool.Report("key-2", directdecisions.Rate{Limit: 100, Remaining: 80, Reset: reset}, nil)
+	pool.Report("key-3", directdecisions.Rate{Limit: 100, Remaining: 0, Reset: reset, Retry: reset}, errors.New("unknown"))
+
+	assertEqual(t, "rate", pool.Rate("key-2"), directdecisions.Rate{Limit: 100, Remaining: 80, Reset: reset})
+	assertEqual(t, "unknown key rate", pool.Rate("key-5"), directdecisions.Rate{})
+
+	// The key with the most remaining requests is preferred.
+	assertEqual(t, "key", key(), "key-2")
+
+	pool.Report("key-2", directdecisions.Rate{Limit: 100, Remaining: 5, Reset: reset}, nil)
+	assertEqual(t, "key", key(), "key-1")
+
+	// Rotated keys keep their rate limit information.
+	pool.SetKeys("key-2", "key-4")
+	assertEqual(t, "key", key(), "key-4")
+	assertEqual(t, "rate", pool.Rate("key-2"), directdecisions.Rate{Limit: 100, Remaining: 5, Reset: reset})
+
+	pool.SetKeys()
+	_, err := pool.Key(context.Background())
+	assertErrors(t, err, directdecisions.ErrNoKeys)
+}
+
+func TestKeyPool_rateLimiter(t *testing.T) {
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Credentials: directdecisions.NewKeyPool("key-1", "key-2"),
+		RateLimiter: true,
+	})
+
+	var keys []string
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("DELETE", func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Authorization"))
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Reset", "10")
+		if r.Header.Get("Authorization") == "Bearer key-1" {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "100")
+	}))
+
+	// Requests with other keys are not delayed by the exhausted key.
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assertErrors(t, client.Votings.Delete(context.Background(), "40f80454800b2bd7c172"), nil)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("got requests completed in %s", d)
+	}
+	assertEqual(t, "keys", keys, []string{"Bearer key-1", "Bearer key-2", "Bearer key-2"})
+}
+
+func TestKeyPool_cache(t *testing.T) {
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Credentials: directdecisions.NewKeyPool("key-1", "key-2"),
+		Cache:       &directdecisions.CacheOptions{TTL: time.Hour},
+	})
+
+	var keys []string
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Authorization"))
+		if r.Method == http.MethodGet {
+			newStaticHandler(`{"id":"40f80454800b2bd7c172","choices":["Margarita","Diavola","Capricciosa"]}`)(w, r)
+		}
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Votings.Voting(context.Background(), "40f80454800b2bd7c172")
+		assertErrors(t, err, nil)
+	}
+	assertErrors(t, client.Votings.Delete(context.Background(), "40f80454800b2bd7c172"), nil)
+
+	// Keys are not taken for responses served from the cache.
+	assertEqual(t, "keys", keys, []string{"Bearer key-1", "Bearer key-2"})
+}
+
+func TestCredentialsFunc(t *testing.T) {
+	var key string
+	client, mux, _ := newClientWithOptions(t, "ignored", &directdecisions.ClientOptions{
+		Credentials: directdecisions.CredentialsFunc(func(ctx context.Context) (string, error) {
+			if key == "" {
+				return "", errors.New("key not loaded")
+			}
+			return key, nil
+		}),
+	})
+
+	var authorization string
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("DELETE", func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+
+	err := client.Votings.Delete(context.Background(), "40f80454800b2bd7c172")
+	if err == nil || err.Error() != "key not loaded" {
+		t.Errorf("got error %v", err)
+	}
+	assertEqual(t, "authorization", authorization, "")
+
+	key = "loaded-key"
+	assertErrors(t, client.Votings.Delete(context.Background(), "40f80454800b2bd7c172"), nil)
+	assertEqual(t, "authorization", authorization, "Bearer loaded-key")
+}
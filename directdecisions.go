@@ -9,11 +9,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 )
 
 const (
@@ -27,14 +30,29 @@ const (
 
 // Client manages communication with the Direct Decisions API.
 type Client struct {
-	httpClient *http.Client // HTTP client must handle authentication implicitly.
-	service    service      // Reuse a single struct instead of allocating one for each service on the heap.
+	httpClient  *http.Client // HTTP client that resolves request paths against the base URL.
+	baseURL     *url.URL     // API base URL that request paths are resolved against.
+	credentials Credentials  // Provides API keys for authentication headers of requests.
+	service     service      // Reuse a single struct instead of allocating one for each service on the heap.
 
 	// rate contains the current rate limit for the client as determined
 	// by the most recent API call.
 	rate   Rate
 	rateMu sync.RWMutex
 
+	retrier   *retrier
+	limiters  *keyLimiters
+	handler   Handler
+	logger    *logger
+	tracer    Tracer
+	metrics   Metrics
+	validator *validator
+	cache     *cache
+	breaker   *breaker
+
+	traceOptions    TraceOptions
+	idempotencyKeys bool
+
 	// Services that API provides.
 	Votings *VotingsService
 }
@@ -43,32 +61,106 @@ type Client struct {
 type ClientOptions struct {
 	HTTPClient *http.Client
 	BaseURL    *url.URL
+	// Retry enables automatic retries of failed requests if it is not nil.
+	Retry *RetryPolicy
+	// RateLimiter enables delaying of requests based on the rate limit
+	// information from the most recent response to a request with the same
+	// API key. Requests are evenly spread over the rest of the rate limit
+	// window and they are blocked when no more requests are remaining until
+	// the window resets.
+	RateLimiter bool
+	// Middleware intercepts every HTTP request that the Client sends. The
+	// first Middleware in the list is the outermost one.
+	Middleware []Middleware
+	// Logger, if not nil, receives one record for every API operation with
+	// its method, path, status, duration, remaining rate limit, number of
+	// attempts and error classification.
+	Logger *slog.Logger
+	// LogOptions configures the content of Logger records. If it is nil,
+	// bodies are not logged and sensitive values are redacted.
+	LogOptions *LogOptions
+	// Tracer, if not nil, is notified about the start and the end of every
+	// API operation.
+	Tracer Tracer
+	// TraceOptions configures the content of Tracer spans. If it is nil,
+	// sensitive values are redacted.
+	TraceOptions *TraceOptions
+	// Metrics, if not nil, receives measurements of every API operation.
+	Metrics Metrics
+	// Limits, if not nil, enables validation of VotingsService method
+	// arguments against the limits before sending requests, so that invalid
+	// arguments are rejected without a network round trip.
+	Limits *Limits
+	// IdempotencyKeys enables sending of a random Idempotency-Key header
+	// with every POST request, reused across its retries. Keys can also be
+	// provided for individual operations with ContextWithIdempotency. POST
+	// requests with idempotency keys are retried as the ones with idempotent
+	// HTTP methods.
+	IdempotencyKeys bool
+	// Cache, if not nil, enables caching of responses to GET requests.
+	Cache *CacheOptions
+	// CircuitBreaker, if not nil, enables the circuit breaker that fails
+	// requests with ErrCircuitOpen, without sending them, while the API is
+	// failing.
+	CircuitBreaker *CircuitBreakerOptions
+	// Credentials, if not nil, provides API keys for requests instead of the
+	// key passed to NewClient. If a request is rejected with
+	// ErrHTTPStatusUnauthorized and Credentials return a different key, the
+	// request is repeated with it.
+	Credentials Credentials
 }
 
-// NewClient constructs a new Client that uses API key authentication.
+// NewClient constructs a new Client that uses API key authentication with the
+// key or with the keys provided by ClientOptions Credentials.
 func NewClient(key string, o *ClientOptions) (c *Client) {
 	if o == nil {
 		o = new(ClientOptions)
 	}
-	authFunc := func(r *http.Request) {}
-	if key != "" {
-		authFunc = func(r *http.Request) {
-			r.Header.Set("Authorization", "Bearer "+key)
-		}
+	credentials := o.Credentials
+	if credentials == nil && key != "" {
+		credentials = staticKey(key)
+	}
+	baseURL := o.BaseURL
+	if baseURL == nil {
+		baseURL, _ = url.Parse(defaultBaseURL)
+	}
+	if !strings.HasSuffix(baseURL.Path, "/") {
+		baseURL.Path += "/"
+	}
+	c = newClient(httpClientWithTransport(o.HTTPClient, baseURL), credentials)
+	c.baseURL = baseURL
+	c.handler = chain(c.handler, o.Middleware)
+	c.logger = newLogger(o.Logger, o.LogOptions)
+	c.tracer = o.Tracer
+	if o.TraceOptions != nil {
+		c.traceOptions = *o.TraceOptions
 	}
-	return newClient(httpClientWithTransport(o.HTTPClient, o.BaseURL, authFunc))
+	c.metrics = o.Metrics
+	c.validator = newValidator(o.Limits)
+	c.idempotencyKeys = o.IdempotencyKeys
+	c.cache = newCache(o.Cache)
+	c.breaker = newBreaker(o.CircuitBreaker)
+	c.retrier = newRetrier(o.Retry)
+	if o.RateLimiter {
+		c.limiters = new(keyLimiters)
+	}
+	return c
 }
 
-// newClient constructs a new *Client with the provided http Client, which
-// should handle authentication implicitly, and sets all API services.
-func newClient(httpClient *http.Client) (c *Client) {
-	c = &Client{httpClient: httpClient}
+// newClient constructs a new *Client with the provided http Client and
+// credentials, and sets all API services.
+func newClient(httpClient *http.Client, credentials Credentials) (c *Client) {
+	c = &Client{
+		httpClient:  httpClient,
+		credentials: credentials,
+	}
+	c.handler = c.send
 	c.service.client = c
 	c.Votings = (*VotingsService)(&c.service)
 	return c
 }
 
-func httpClientWithTransport(c *http.Client, baseURL *url.URL, authFunc func(r *http.Request)) *http.Client {
+func httpClientWithTransport(c *http.Client, baseURL *url.URL) *http.Client {
 	if c == nil {
 		c = new(http.Client)
 	}
@@ -78,16 +170,10 @@ func httpClientWithTransport(c *http.Client, baseURL *url.URL, authFunc func(r *
 		transport = http.DefaultTransport
 	}
 
-	if baseURL == nil {
-		baseURL, _ = url.Parse(defaultBaseURL)
-	}
-	if !strings.HasSuffix(baseURL.Path, "/") {
-		baseURL.Path += "/"
-	}
-
 	c.Transport = roundTripperFunc(func(r *http.Request) (resp *http.Response, err error) {
+		// Do not modify the request that may be inspected by middleware.
+		r = r.Clone(r.Context())
 		r.Header.Set("User-Agent", userAgent)
-		authFunc(r)
 		u, err := baseURL.Parse(r.URL.String())
 		if err != nil {
 			return nil, err
@@ -100,45 +186,212 @@ func httpClientWithTransport(c *http.Client, baseURL *url.URL, authFunc func(r *
 
 // request handles the HTTP request response cycle. It JSON encodes the request
 // body, creates an HTTP request with provided method on a path with required
-// headers, sets current request rate information to the Client and decodes
-// request body if the v argument is not nil and content type is
-// application/json.
-func (c *Client) request(ctx context.Context, method, path string, body, v interface{}) (err error) {
-	var bodyBuffer io.ReadWriter
+// headers, passes it through the Client's middleware chain to be sent, and
+// decodes request body if the v argument is not nil and content type is
+// application/json. Requests are repeated according to the Client's retry
+// policy, delayed by its rate limiter and rejected by its circuit breaker, and
+// responses to GET requests are served from the Client's cache when possible.
+// The op argument is the name of the API operation, such as "Votings.Vote",
+// used for logging and tracing.
+func (c *Client) request(ctx context.Context, op, method, path string, body, v interface{}) (err error) {
+	var data []byte
 	if body != nil {
-		bodyBuffer = new(bytes.Buffer)
-		if err = encodeJSON(bodyBuffer, body); err != nil {
+		buf := new(bytes.Buffer)
+		if err = encodeJSON(buf, body); err != nil {
 			return err
 		}
+		data = buf.Bytes()
 	}
 
-	req, err := http.NewRequest(method, path, bodyBuffer)
+	idempotency, err := c.idempotency(ctx, op, method, path)
 	if err != nil {
 		return err
 	}
-	req = req.WithContext(ctx)
 
-	if body != nil {
+	var call *Call
+	start := time.Now()
+	ctx, span := c.startSpan(ctx, op, method, path, start)
+	defer func() {
+		d := time.Since(start)
+		c.logger.log(ctx, op, method, path, call, data, d, err)
+		c.observe(op, call, d, err)
+		c.endSpan(ctx, span, call, err)
+		recordAttempts(ctx, call)
+	}()
+
+	var (
+		failoverKey  string
+		rejectedKeys map[string]struct{}
+	)
+	var (
+		cacheScope string
+		sent       int
+	)
+	for attempt := 1; ; attempt++ {
+		key := failoverKey
+		failoverKey = ""
+		if cacheScope, key, err = c.cacheScope(ctx, key); err != nil {
+			return err
+		}
+		cacheKey := cacheScope + "/" + path
+		cached, fresh := c.cache.lookup(method, cacheKey)
+
+		req, reqErr := newRequest(ctx, method, path, data)
+		if reqErr != nil {
+			return reqErr
+		}
+		if !fresh {
+			// The key is resolved only for requests that are sent.
+			if key == "" {
+				if key, err = c.key(ctx); err != nil {
+					return err
+				}
+			}
+			if err := c.limiters.limiter(key).wait(ctx); err != nil {
+				return err
+			}
+		}
+		setAuthorization(req, key)
+		setTraceHeaders(req)
+		setIdempotencyKey(req, idempotency)
+		setConditionalHeaders(req, cached)
+
+		call = &Call{
+			Operation: op,
+			Attempt:   attempt,
+			Request:   req,
+			path:      path,
+			sent:      sent,
+			key:       key,
+			cached:    cached,
+			cacheKey:  cacheKey,
+		}
+		if fresh {
+			call.Response = cached.response(req)
+			call.responseBody = cached.Body
+			call.cacheHit = true
+			break
+		}
+		var trial bool
+		if trial, err = c.breaker.allow(time.Now()); err != nil {
+			break
+		}
+		sent++
+		call.sent = sent
+		err = c.handler(call)
+		c.breaker.done(trial, err, time.Now())
+		c.reportKey(key, call.rate, err)
+
+		if key != "" && errors.Is(err, ErrHTTPStatusUnauthorized) {
+			// Fail over to a key that is not rejected yet.
+			if rejectedKeys == nil {
+				rejectedKeys = make(map[string]struct{})
+			}
+			rejectedKeys[key] = struct{}{}
+			if next, keyErr := c.key(ctx); keyErr == nil {
+				if _, ok := rejectedKeys[next]; !ok {
+					if call.Response != nil {
+						drain(call.Response.Body)
+					}
+					failoverKey = next
+					continue
+				}
+			}
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			break
+		}
+		d, ok := c.retrier.delay(isIdempotent(method) || idempotency != nil, apiErr.StatusCode, attempt, apiErr.Rate)
+		if !ok {
+			break
+		}
+		if call.Response != nil {
+			drain(call.Response.Body)
+		}
+		if err := sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+
+	r := call.Response
+	if r != nil {
+		defer drain(r.Body)
+	}
+	setIdempotencyReplayed(idempotency, r)
+	c.cache.invalidate(method, cacheScope, path)
+
+	if err != nil {
+		return err
+	}
+	if r == nil {
+		return ErrNoResponse
+	}
+
+	if v != nil && strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		return json.NewDecoder(r.Body).Decode(&v)
+	}
+	return nil
+}
+
+// newRequest creates an HTTP request with the provided method on a path with
+// the JSON encoded body data, if it is not nil.
+func newRequest(ctx context.Context, method, path string, data []byte) (*http.Request, error) {
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if data != nil {
 		req.Header.Set("Content-Type", contentType)
 	}
 	req.Header.Set("Accept", contentType)
 
-	r, err := c.httpClient.Do(req)
+	return req, nil
+}
+
+// send is the last Handler in the middleware chain that sends the request of
+// the call, sets current request rate information to the Client and returns
+// the error based on the response status. A 304 Not Modified response is
+// replaced with the cached one.
+func (c *Client) send(call *Call) error {
+	r, err := c.httpClient.Do(call.Request)
 	if err != nil {
 		return err
 	}
-	defer drain(r.Body)
 
-	c.setRate(r)
+	call.rate = c.setRate(r)
+	c.limiters.limiter(call.key).update(call.rate)
+	c.observeRate(call.rate)
 
-	if err := responseErrorHandler(r); err != nil {
-		return err
+	if r.StatusCode == http.StatusNotModified && call.cached != nil {
+		drain(r.Body)
+		c.cache.refresh(call.cacheKey, call.cached)
+		r = call.cached.response(call.Request)
 	}
+	call.Response = r
 
-	if v != nil && strings.Contains(r.Header.Get("Content-Type"), "application/json") {
-		return json.NewDecoder(r.Body).Decode(&v)
+	if c.logger.logBodies(call.Request.Context()) {
+		b, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return err
+		}
+		call.responseBody = b
+		r.Body = io.NopCloser(bytes.NewReader(b))
 	}
-	return nil
+
+	if err := c.cache.store(call, r); err != nil {
+		return err
+	}
+
+	return responseErrorHandler(r, call.Request.Method, call.path, call.rate)
 }
 
 // encodeJSON writes a JSON-encoded v object to the provided writer with
@@ -56,16 +56,21 @@ const jsonContentType = "application/json; charset=utf-8"
 func newClient(t testing.TB, key string) (client *directdecisions.Client, mux *http.ServeMux, baseURL *url.URL) {
 	t.Helper()
 
+	return newClientWithOptions(t, key, new(directdecisions.ClientOptions))
+}
+
+func newClientWithOptions(t testing.TB, key string, o *directdecisions.ClientOptions) (client *directdecisions.Client, mux *http.ServeMux, baseURL *url.URL) {
+	t.Helper()
+
 	mux = http.NewServeMux()
 	server := httptest.NewServer(mux)
 
 	baseURL, err := url.Parse(server.URL)
 	assertErrors(t, err, nil)
 
-	client = directdecisions.NewClient(key, &directdecisions.ClientOptions{
-		BaseURL:    baseURL,
-		HTTPClient: server.Client(),
-	})
+	o.BaseURL = baseURL
+	o.HTTPClient = server.Client()
+	client = directdecisions.NewClient(key, o)
 
 	t.Cleanup(server.Close)
 
@@ -0,0 +1,596 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package directdecisionstest provides an in-memory implementation of the
+// Direct Decisions API v1 for testing code that uses the directdecisions
+// client without network access.
+package directdecisionstest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"directdecisions.com/directdecisions"
+	"directdecisions.com/directdecisions/schulze"
+)
+
+const (
+	defaultRateWindow       = time.Hour
+	defaultMaxChoices       = 100
+	defaultMaxChoiceLength  = 256
+	defaultMaxVoterIDLength = 256
+)
+
+// Options holds optional parameters for the Handler.
+type Options struct {
+	// Key is the API key that requests must be authenticated with. If it is
+	// empty, authentication is not required.
+	Key string
+	// RateLimit is the maximal number of requests permitted in a RateWindow.
+	// If it is zero, requests are not limited and rate limit headers are
+	// not sent.
+	RateLimit int
+	// RateWindow is the duration of the rate limit window. If it is zero,
+	// one hour is used.
+	RateWindow time.Duration
+	// MaxChoices is the maximal number of choices in a voting. If it is
+	// zero, 100 is used.
+	MaxChoices int
+	// MaxChoiceLength is the maximal length of a choice in bytes. If it is
+	// zero, 256 is used.
+	MaxChoiceLength int
+	// MaxVoterIDLength is the maximal length of a voter ID in bytes. If it
+	// is zero, 256 is used.
+	MaxVoterIDLength int
+}
+
+// Handler is an http.Handler that serves the Direct Decisions API v1 from
+// memory. POST requests with the Idempotency-Key header are performed once
+// for every key and path, and repeated ones receive the stored response. It is
+// safe for concurrent use.
+type Handler struct {
+	o Options
+
+	mu      sync.Mutex
+	votings map[string]*voting
+
+	rateMu     sync.Mutex
+	rateCount  int
+	rateWindow time.Time
+
+	replaysMu sync.Mutex
+	replays   map[string]*httptest.ResponseRecorder // by idempotency key and path
+}
+
+type voting struct {
+	choices []string
+	ballots map[string]map[string]int
+}
+
+// NewHandler returns a new Handler with no votings.
+func NewHandler(o *Options) *Handler {
+	if o == nil {
+		o = new(Options)
+	}
+	h := &Handler{
+		o:       *o,
+		votings: make(map[string]*voting),
+		replays: make(map[string]*httptest.ResponseRecorder),
+	}
+	if h.o.RateWindow <= 0 {
+		h.o.RateWindow = defaultRateWindow
+	}
+	if h.o.MaxChoices <= 0 {
+		h.o.MaxChoices = defaultMaxChoices
+	}
+	if h.o.MaxChoiceLength <= 0 {
+		h.o.MaxChoiceLength = defaultMaxChoiceLength
+	}
+	if h.o.MaxVoterIDLength <= 0 {
+		h.o.MaxVoterIDLength = defaultMaxVoterIDLength
+	}
+	return h
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.o.Key != "" && r.Header.Get("Authorization") != "Bearer "+h.o.Key {
+		writeError(w, http.StatusUnauthorized)
+		return
+	}
+
+	if !h.allow(w) {
+		return
+	}
+
+	if key := r.Header.Get("Idempotency-Key"); key != "" && r.Method == http.MethodPost {
+		h.serveIdempotent(w, r, key)
+		return
+	}
+
+	h.serve(w, r)
+}
+
+// serveIdempotent responds to a POST request with the same response as to the
+// previous request with the same idempotency key and path, marked with the
+// Idempotent-Replayed header. Responses with server error statuses are not
+// stored.
+func (h *Handler) serveIdempotent(w http.ResponseWriter, r *http.Request, key string) {
+	h.replaysMu.Lock()
+	defer h.replaysMu.Unlock()
+
+	id := key + " " + r.URL.EscapedPath()
+	rec, ok := h.replays[id]
+	if ok {
+		w.Header().Set("Idempotent-Replayed", "true")
+	} else {
+		rec = httptest.NewRecorder()
+		h.serve(rec, r)
+		if rec.Code < http.StatusInternalServerError {
+			h.replays[id] = rec
+		}
+	}
+
+	for k, v := range rec.Header() {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(rec.Code)
+	_, _ = w.Write(rec.Body.Bytes())
+}
+
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request) {
+	path, ok := strings.CutPrefix(r.URL.EscapedPath(), "/v1/votings")
+	if !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, s := range segments {
+		s, err := url.PathUnescape(s)
+		if err != nil {
+			writeError(w, http.StatusNotFound)
+			return
+		}
+		segments[i] = s
+	}
+
+	switch {
+	case path == "":
+		h.route(w, r, map[string]http.HandlerFunc{
+			http.MethodPost: h.createHandler,
+		})
+	case len(segments) == 1:
+		h.route(w, r, map[string]http.HandlerFunc{
+			http.MethodGet:    h.votingHandler(segments[0]),
+			http.MethodDelete: h.deleteHandler(segments[0]),
+		})
+	case len(segments) == 2 && segments[1] == "choices":
+		h.route(w, r, map[string]http.HandlerFunc{
+			http.MethodPost: h.setHandler(segments[0]),
+		})
+	case len(segments) == 3 && segments[1] == "ballots":
+		h.route(w, r, map[string]http.HandlerFunc{
+			http.MethodGet:    h.ballotHandler(segments[0], segments[2]),
+			http.MethodPost:   h.voteHandler(segments[0], segments[2]),
+			http.MethodDelete: h.unvoteHandler(segments[0], segments[2]),
+		})
+	case len(segments) == 2 && segments[1] == "results":
+		h.route(w, r, map[string]http.HandlerFunc{
+			http.MethodGet: h.resultsHandler(segments[0], false),
+		})
+	case len(segments) == 3 && segments[1] == "results" && segments[2] == "duels":
+		h.route(w, r, map[string]http.HandlerFunc{
+			http.MethodGet: h.resultsHandler(segments[0], true),
+		})
+	default:
+		writeError(w, http.StatusNotFound)
+	}
+}
+
+func (h *Handler) route(w http.ResponseWriter, r *http.Request, handlers map[string]http.HandlerFunc) {
+	f, ok := handlers[r.Method]
+	if !ok {
+		writeError(w, http.StatusMethodNotAllowed)
+		return
+	}
+	f(w, r)
+}
+
+// allow sets rate limit headers and responds with the Too Many Requests status
+// if the rate limit is reached, returning false.
+func (h *Handler) allow(w http.ResponseWriter) bool {
+	if h.o.RateLimit <= 0 {
+		return true
+	}
+
+	h.rateMu.Lock()
+	defer h.rateMu.Unlock()
+
+	now := time.Now()
+	if !h.rateWindow.After(now) {
+		h.rateWindow = now.Add(h.o.RateWindow)
+		h.rateCount = 0
+	}
+	reset := strconv.Itoa(int(h.rateWindow.Sub(now).Round(time.Second) / time.Second))
+
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(h.o.RateLimit))
+	w.Header().Set("X-RateLimit-Reset", reset)
+
+	if h.rateCount >= h.o.RateLimit {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("Retry-After", reset)
+		writeError(w, http.StatusTooManyRequests)
+		return false
+	}
+	h.rateCount++
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(h.o.RateLimit-h.rateCount))
+	return true
+}
+
+func (h *Handler) createHandler(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Choices []string `json:"choices"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid Data")
+		return
+	}
+	if len(request.Choices) == 0 {
+		writeError(w, http.StatusBadRequest, "Missing Choices")
+		return
+	}
+	if len(request.Choices) > h.o.MaxChoices {
+		writeError(w, http.StatusBadRequest, "Too Many Choices")
+		return
+	}
+	seen := make(map[string]struct{}, len(request.Choices))
+	for _, c := range request.Choices {
+		if msg := h.validateChoice(c); msg != "" {
+			writeError(w, http.StatusBadRequest, msg)
+			return
+		}
+		if _, ok := seen[c]; ok {
+			writeError(w, http.StatusBadRequest, "Invalid Data")
+			return
+		}
+		seen[c] = struct{}{}
+	}
+
+	id, err := newID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError)
+		return
+	}
+
+	h.mu.Lock()
+	h.votings[id] = &voting{
+		choices: request.Choices,
+		ballots: make(map[string]map[string]int),
+	}
+	h.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, votingResponse(id, request.Choices))
+}
+
+func (h *Handler) votingHandler(id string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		v, ok := h.votings[id]
+		if !ok {
+			writeError(w, http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, votingResponse(id, v.choices))
+	}
+}
+
+func (h *Handler) deleteHandler(id string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if _, ok := h.votings[id]; !ok {
+			writeError(w, http.StatusNotFound)
+			return
+		}
+		delete(h.votings, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// setHandler adds a choice at the index, moves an existing choice to the index
+// or removes the choice if the index is negative.
+func (h *Handler) setHandler(id string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Choice string `json:"choice"`
+			Index  int    `json:"index"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid Data")
+			return
+		}
+		if msg := h.validateChoice(request.Choice); msg != "" {
+			writeError(w, http.StatusBadRequest, msg)
+			return
+		}
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		v, ok := h.votings[id]
+		if !ok {
+			writeError(w, http.StatusNotFound)
+			return
+		}
+
+		choices := make([]string, 0, len(v.choices)+1)
+		for _, c := range v.choices {
+			if c != request.Choice {
+				choices = append(choices, c)
+			}
+		}
+		if request.Index >= 0 {
+			if len(choices) >= h.o.MaxChoices {
+				writeError(w, http.StatusBadRequest, "Too Many Choices")
+				return
+			}
+			i := request.Index
+			if i > len(choices) {
+				i = len(choices)
+			}
+			choices = append(choices[:i], append([]string{request.Choice}, choices[i:]...)...)
+		}
+		v.choices = choices
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"choices": choices,
+		})
+	}
+}
+
+func (h *Handler) ballotHandler(id, voterID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if msg := h.validateVoterID(voterID); msg != "" {
+			writeError(w, http.StatusBadRequest, msg)
+			return
+		}
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		v, ok := h.votings[id]
+		if !ok {
+			writeError(w, http.StatusNotFound)
+			return
+		}
+		b, ok := v.ballots[voterID]
+		if !ok {
+			writeError(w, http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"ballot": b,
+		})
+	}
+}
+
+func (h *Handler) voteHandler(id, voterID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Ballot map[string]int `json:"ballot"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid Data")
+			return
+		}
+
+		var msgs []string
+		if msg := h.validateVoterID(voterID); msg != "" {
+			msgs = append(msgs, msg)
+		}
+		if len(request.Ballot) == 0 {
+			msgs = append(msgs, "Ballot Required")
+		}
+		if len(msgs) > 0 {
+			writeError(w, http.StatusBadRequest, msgs...)
+			return
+		}
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		v, ok := h.votings[id]
+		if !ok {
+			writeError(w, http.StatusNotFound)
+			return
+		}
+		for c, rank := range request.Ballot {
+			if rank <= 0 || !contains(v.choices, c) {
+				writeError(w, http.StatusBadRequest, "Invalid Data")
+				return
+			}
+		}
+
+		_, revoted := v.ballots[voterID]
+		v.ballots[voterID] = request.Ballot
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"revoted": revoted,
+		})
+	}
+}
+
+func (h *Handler) unvoteHandler(id, voterID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if msg := h.validateVoterID(voterID); msg != "" {
+			writeError(w, http.StatusBadRequest, msg)
+			return
+		}
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		v, ok := h.votings[id]
+		if !ok {
+			writeError(w, http.StatusNotFound)
+			return
+		}
+		if _, ok := v.ballots[voterID]; !ok {
+			writeError(w, http.StatusNotFound)
+			return
+		}
+		delete(v.ballots, voterID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) resultsHandler(id string, withDuels bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.mu.Lock()
+		v, ok := h.votings[id]
+		if !ok {
+			h.mu.Unlock()
+			writeError(w, http.StatusNotFound)
+			return
+		}
+		choices := append([]string(nil), v.choices...)
+		ballots := make([]map[string]int, 0, len(v.ballots))
+		for _, b := range v.ballots {
+			// Ignore ranks of choices that were removed after voting.
+			ballot := make(map[string]int, len(b))
+			for c, r := range b {
+				if contains(choices, c) {
+					ballot[c] = r
+				}
+			}
+			ballots = append(ballots, ballot)
+		}
+		h.mu.Unlock()
+
+		results, duels, tie, err := schulze.Duels(choices, ballots)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError)
+			return
+		}
+
+		response := map[string]any{
+			"results": results,
+			"tie":     tie,
+		}
+		if withDuels {
+			response["duels"] = duels
+		}
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+func (h *Handler) validateChoice(c string) (msg string) {
+	if strings.TrimSpace(c) == "" {
+		return "Choice Required"
+	}
+	if len(c) > h.o.MaxChoiceLength {
+		return "Choice Too Long"
+	}
+	return ""
+}
+
+func (h *Handler) validateVoterID(id string) (msg string) {
+	if len(id) > h.o.MaxVoterIDLength {
+		return "Voter ID Too Long"
+	}
+	if id == "" || !utf8.ValidString(id) || strings.IndexFunc(id, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r)
+	}) >= 0 {
+		return "Invalid Voter ID"
+	}
+	return ""
+}
+
+// Server is an HTTP server that serves the Direct Decisions API v1 from
+// memory on a system-chosen port on the local loopback interface.
+type Server struct {
+	*httptest.Server
+	Handler *Handler
+}
+
+// NewServer starts and returns a new Server. The caller should call Close when
+// finished, to shut it down.
+func NewServer(o *Options) *Server {
+	h := NewHandler(o)
+	return &Server{
+		Server:  httptest.NewServer(h),
+		Handler: h,
+	}
+}
+
+// NewClient returns a new directdecisions Client with the API key that sends
+// requests to the Server. Options BaseURL and HTTPClient are set by this
+// method.
+func (s *Server) NewClient(key string, o *directdecisions.ClientOptions) *directdecisions.Client {
+	var opts directdecisions.ClientOptions
+	if o != nil {
+		opts = *o
+	}
+	opts.BaseURL, _ = url.Parse(s.URL)
+	opts.HTTPClient = s.Client()
+	return directdecisions.NewClient(key, &opts)
+}
+
+func votingResponse(id string, choices []string) map[string]any {
+	return map[string]any{
+		"id":      id,
+		"choices": choices,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response with the body that the directdecisions
+// client decodes into errors.
+func writeError(w http.ResponseWriter, status int, errors ...string) {
+	writeJSON(w, status, struct {
+		Message string   `json:"message"`
+		Code    int      `json:"code"`
+		Errors  []string `json:"errors,omitempty"`
+	}{
+		Message: http.StatusText(status),
+		Code:    status,
+		Errors:  errors,
+	})
+}
+
+func newID() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func contains(s []string, e string) bool {
+	for _, v := range s {
+		if v == e {
+			return true
+		}
+	}
+	return false
+}
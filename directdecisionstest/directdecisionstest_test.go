@@ -0,0 +1,287 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisionstest_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"directdecisions.com/directdecisions"
+	"directdecisions.com/directdecisions/directdecisionstest"
+)
+
+func TestServer(t *testing.T) {
+	client := newClient(t, &directdecisionstest.Options{Key: "my-key"}, "my-key")
+
+	ctx := context.Background()
+
+	v, err := client.Votings.Create(ctx, []string{"Margarita", "Pepperoni", "Capricciosa"})
+	assertErrors(t, err, nil)
+
+	got, err := client.Votings.Voting(ctx, v.ID)
+	assertErrors(t, err, nil)
+	assertEqual(t, "voting", got, v)
+
+	choices, err := client.Votings.Set(ctx, v.ID, "Diavola", 1)
+	assertErrors(t, err, nil)
+	assertEqual(t, "choices", choices, []string{"Margarita", "Diavola", "Pepperoni", "Capricciosa"})
+
+	choices, err = client.Votings.Set(ctx, v.ID, "Capricciosa", 0)
+	assertErrors(t, err, nil)
+	assertEqual(t, "choices", choices, []string{"Capricciosa", "Margarita", "Diavola", "Pepperoni"})
+
+	choices, err = client.Votings.Set(ctx, v.ID, "Diavola", -1)
+	assertErrors(t, err, nil)
+	assertEqual(t, "choices", choices, []string{"Capricciosa", "Margarita", "Pepperoni"})
+
+	for voterID, ballot := range map[string]map[string]int{
+		"leonardo":     {"Pepperoni": 1, "Margarita": 2},
+		"michelangelo": {"Capricciosa": 1, "Margarita": 2, "Pepperoni": 2},
+		"raphael":      {"Margarita": 1, "Capricciosa": 2},
+		"donatello":    {"Margarita": 1},
+	} {
+		revoted, err := client.Votings.Vote(ctx, v.ID, voterID, ballot)
+		assertErrors(t, err, nil)
+		assertEqual(t, "revoted", revoted, false)
+	}
+
+	revoted, err := client.Votings.Vote(ctx, v.ID, "leonardo", map[string]int{"Pepperoni": 1})
+	assertErrors(t, err, nil)
+	assertEqual(t, "revoted", revoted, true)
+
+	ballot, err := client.Votings.Ballot(ctx, v.ID, "leonardo")
+	assertErrors(t, err, nil)
+	assertEqual(t, "ballot", ballot, map[string]int{"Pepperoni": 1})
+
+	assertErrors(t, client.Votings.Unvote(ctx, v.ID, "leonardo"), nil)
+
+	_, err = client.Votings.Ballot(ctx, v.ID, "leonardo")
+	assertErrors(t, err, directdecisions.ErrHTTPStatusNotFound)
+
+	results, tie, err := client.Votings.Results(ctx, v.ID)
+	assertErrors(t, err, nil)
+	assertEqual(t, "tie", tie, false)
+	assertEqual(t, "results", results, []directdecisions.Result{
+		{Choice: "Margarita", Index: 1, Wins: 2, Percentage: 100, Strength: 4, Advantage: 4},
+		{Choice: "Capricciosa", Index: 0, Wins: 1, Percentage: 50, Strength: 2, Advantage: 2},
+		{Choice: "Pepperoni", Index: 2, Wins: 0, Percentage: 0, Strength: 0, Advantage: 0},
+	})
+
+	results, duels, tie, err := client.Votings.Duels(ctx, v.ID)
+	assertErrors(t, err, nil)
+	assertEqual(t, "tie", tie, false)
+	assertEqual(t, "results", len(results), 3)
+	assertEqual(t, "duels", duels, []directdecisions.Duel{
+		{
+			Left:  directdecisions.ChoiceStrength{Choice: "Capricciosa", Index: 0, Strength: 1},
+			Right: directdecisions.ChoiceStrength{Choice: "Margarita", Index: 1, Strength: 2},
+		},
+		{
+			Left:  directdecisions.ChoiceStrength{Choice: "Capricciosa", Index: 0, Strength: 2},
+			Right: directdecisions.ChoiceStrength{Choice: "Pepperoni", Index: 2, Strength: 0},
+		},
+		{
+			Left:  directdecisions.ChoiceStrength{Choice: "Margarita", Index: 1, Strength: 2},
+			Right: directdecisions.ChoiceStrength{Choice: "Pepperoni", Index: 2, Strength: 0},
+		},
+	})
+
+	assertErrors(t, client.Votings.Delete(ctx, v.ID), nil)
+
+	_, err = client.Votings.Voting(ctx, v.ID)
+	assertErrors(t, err, directdecisions.ErrHTTPStatusNotFound)
+}
+
+func TestServer_errors(t *testing.T) {
+	client := newClient(t, &directdecisionstest.Options{
+		MaxChoices:       3,
+		MaxChoiceLength:  10,
+		MaxVoterIDLength: 10,
+	}, "")
+
+	ctx := context.Background()
+
+	v, err := client.Votings.Create(ctx, []string{"Margarita", "Pepperoni"})
+	assertErrors(t, err, nil)
+
+	for _, tc := range []struct {
+		name string
+		call func() error
+		want []error
+	}{
+		{
+			name: "missing choices",
+			call: func() error {
+				_, err := client.Votings.Create(ctx, nil)
+				return err
+			},
+			want: []error{directdecisions.ErrHTTPStatusBadRequest, directdecisions.ErrMissingChoices},
+		},
+		{
+			name: "too many choices",
+			call: func() error {
+				_, err := client.Votings.Create(ctx, []string{"a", "b", "c", "d"})
+				return err
+			},
+			want: []error{directdecisions.ErrHTTPStatusBadRequest, directdecisions.ErrTooManyChoices},
+		},
+		{
+			name: "choice required",
+			call: func() error {
+				_, err := client.Votings.Set(ctx, v.ID, "", 0)
+				return err
+			},
+			want: []error{directdecisions.ErrHTTPStatusBadRequest, directdecisions.ErrChoiceRequired},
+		},
+		{
+			name: "choice too long",
+			call: func() error {
+				_, err := client.Votings.Set(ctx, v.ID, strings.Repeat("a", 11), 0)
+				return err
+			},
+			want: []error{directdecisions.ErrHTTPStatusBadRequest, directdecisions.ErrChoiceTooLong},
+		},
+		{
+			name: "ballot required",
+			call: func() error {
+				_, err := client.Votings.Vote(ctx, v.ID, "leonardo", nil)
+				return err
+			},
+			want: []error{directdecisions.ErrHTTPStatusBadRequest, directdecisions.ErrBallotRequired},
+		},
+		{
+			name: "unknown choice",
+			call: func() error {
+				_, err := client.Votings.Vote(ctx, v.ID, "leonardo", map[string]int{"Diavola": 1})
+				return err
+			},
+			want: []error{directdecisions.ErrHTTPStatusBadRequest, directdecisions.ErrInvalidData},
+		},
+		{
+			name: "voter id too long",
+			call: func() error {
+				_, err := client.Votings.Vote(ctx, v.ID, "leonardo-da-vinci", map[string]int{"Margarita": 1})
+				return err
+			},
+			want: []error{directdecisions.ErrHTTPStatusBadRequest, directdecisions.ErrVoterIDTooLong},
+		},
+		{
+			name: "invalid voter id",
+			call: func() error {
+				_, err := client.Votings.Vote(ctx, v.ID, "leo nardo", map[string]int{"Margarita": 1})
+				return err
+			},
+			want: []error{directdecisions.ErrHTTPStatusBadRequest, directdecisions.ErrInvalidVoterID},
+		},
+		{
+			name: "voting not found",
+			call: func() error {
+				_, _, err := client.Votings.Results(ctx, "missing")
+				return err
+			},
+			want: []error{directdecisions.ErrHTTPStatusNotFound},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assertErrors(t, tc.call(), tc.want...)
+		})
+	}
+}
+
+func TestServer_unauthorized(t *testing.T) {
+	client := newClient(t, &directdecisionstest.Options{Key: "my-key"}, "other-key")
+
+	_, err := client.Votings.Create(context.Background(), []string{"Margarita", "Pepperoni"})
+	assertErrors(t, err, directdecisions.ErrHTTPStatusUnauthorized)
+}
+
+func TestServer_rateLimit(t *testing.T) {
+	client := newClient(t, &directdecisionstest.Options{RateLimit: 2}, "")
+
+	ctx := context.Background()
+
+	v, err := client.Votings.Create(ctx, []string{"Margarita", "Pepperoni"})
+	assertErrors(t, err, nil)
+
+	rate := client.Rate()
+	assertEqual(t, "limit", rate.Limit, 2)
+	assertEqual(t, "remaining", rate.Remaining, 1)
+
+	_, err = client.Votings.Voting(ctx, v.ID)
+	assertErrors(t, err, nil)
+	assertEqual(t, "remaining", client.Rate().Remaining, 0)
+
+	_, err = client.Votings.Voting(ctx, v.ID)
+	assertErrors(t, err, directdecisions.ErrHTTPStatusTooManyRequests)
+	if client.Rate().Retry.IsZero() {
+		t.Error("got zero retry time")
+	}
+}
+
+func TestServer_idempotency(t *testing.T) {
+	client := newClient(t, nil, "")
+
+	i := &directdecisions.Idempotency{Key: "create-pizza-voting"}
+	ctx := directdecisions.ContextWithIdempotency(context.Background(), i)
+
+	v1, err := client.Votings.Create(ctx, []string{"Margarita", "Pepperoni"})
+	assertErrors(t, err, nil)
+	assertEqual(t, "replayed", i.Replayed, false)
+
+	v2, err := client.Votings.Create(ctx, []string{"Margarita", "Pepperoni"})
+	assertErrors(t, err, nil)
+	assertEqual(t, "replayed", i.Replayed, true)
+	assertEqual(t, "voting", v2, v1)
+
+	// The same key for a different path is a different operation.
+	i = &directdecisions.Idempotency{Key: "create-pizza-voting"}
+	ctx = directdecisions.ContextWithIdempotency(context.Background(), i)
+	revoted, err := client.Votings.Vote(ctx, v1.ID, "leonardo", map[string]int{"Margarita": 1})
+	assertErrors(t, err, nil)
+	assertEqual(t, "replayed", i.Replayed, false)
+	assertEqual(t, "revoted", revoted, false)
+
+	revoted, err = client.Votings.Vote(ctx, v1.ID, "leonardo", map[string]int{"Margarita": 1})
+	assertErrors(t, err, nil)
+	assertEqual(t, "replayed", i.Replayed, true)
+	assertEqual(t, "revoted", revoted, false)
+}
+
+func newClient(t testing.TB, o *directdecisionstest.Options, key string) *directdecisions.Client {
+	t.Helper()
+
+	s := directdecisionstest.NewServer(o)
+	t.Cleanup(s.Close)
+
+	return s.NewClient(key, nil)
+}
+
+func assertEqual(t testing.TB, name string, got, want any) {
+	t.Helper()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s: got %+v, want %+v", name, got, want)
+	}
+}
+
+func assertErrors(t testing.TB, got error, want ...error) {
+	t.Helper()
+
+	if len(want) == 1 && want[0] == nil {
+		if got != nil {
+			t.Fatalf("got error %v, want no error", got)
+		}
+		return
+	}
+	for _, w := range want {
+		if !errors.Is(got, w) {
+			t.Fatalf("got error %v, want %v", got, w)
+		}
+	}
+}
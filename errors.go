@@ -14,7 +14,7 @@ import (
 	"strings"
 )
 
-// Errors that are returned by the API.
+// Errors that are returned by the API and by the Client.
 var (
 	ErrHTTPStatusBadRequest          = errors.New("http status: " + http.StatusText(http.StatusBadRequest))
 	ErrHTTPStatusUnauthorized        = errors.New("http status: " + http.StatusText(http.StatusUnauthorized))
@@ -34,6 +34,18 @@ var (
 	ErrBallotRequired = errors.New("Ballot Required")
 	ErrVoterIDTooLong = errors.New("Voter ID Too Long")
 	ErrInvalidVoterID = errors.New("Invalid Voter ID")
+
+	// ErrCircuitOpen is returned by the Client, without sending a request,
+	// when the circuit breaker is open.
+	ErrCircuitOpen = errors.New("circuit open")
+	// ErrNoResponse is returned by the Client when a Middleware returns a
+	// nil error without setting the Call Response.
+	ErrNoResponse = errors.New("handler returned without a response")
+	// ErrNoKeys is returned by KeyPool when it has no keys.
+	ErrNoKeys = errors.New("no keys")
+	// ErrMissingKey is returned by NewClientFromEnv and Config Validate when
+	// the API key is not found in any of the configuration sources.
+	ErrMissingKey = errors.New("missing api key")
 )
 
 var statusToError = map[int]error{
@@ -59,47 +71,102 @@ var messageToError = map[string]error{
 	"Invalid Voter ID":  ErrInvalidVoterID,
 }
 
+// isValidationError returns true if the error matches any of the errors for
+// known messages, such as ErrInvalidData, which are also returned by the
+// validation of arguments without sending a request.
+func isValidationError(err error) bool {
+	for _, e := range messageToError {
+		if errors.Is(err, e) {
+			return true
+		}
+	}
+	return false
+}
+
 type messageResponse struct {
 	Message string   `json:"message,omitempty"`
 	Code    int      `json:"code,omitempty"`
 	Errors  []string `json:"errors,omitempty"`
 }
 
-// responseErrorHandler returns an error based on the HTTP status code or nil if
-// the status code is from 200 to 299.
-func responseErrorHandler(r *http.Response) error {
+// APIError is returned by the Client when the API responds with a status code
+// that is not from 200 to 299. It matches the ErrHTTPStatus* error that
+// corresponds to the response status code and errors like ErrInvalidData for
+// every known message from the response body with errors.Is.
+type APIError struct {
+	StatusCode int         // HTTP response status code.
+	Message    string      // Message from the response body.
+	Code       int         // Code from the response body.
+	Errors     []string    // Error messages from the response body.
+	Method     string      // HTTP method of the request.
+	Path       string      // Path of the request relative to the base URL.
+	Header     http.Header // HTTP response headers.
+	Rate       Rate        // Rate limit information from the response.
+
+	decodeErr error // error from decoding the response body
+}
+
+// Error returns messages of all errors that the APIError matches, one per line.
+func (e *APIError) Error() string {
+	return errors.Join(e.Unwrap()...).Error()
+}
+
+// Unwrap returns all errors that the APIError matches.
+func (e *APIError) Unwrap() []error {
+	statusErr, ok := statusToError[e.StatusCode]
+	if !ok {
+		statusErr = errors.New("http status: " + http.StatusText(e.StatusCode))
+	}
+
+	errs := []error{
+		statusErr,
+	}
+
+	if e.decodeErr != nil {
+		errs = append(errs, e.decodeErr)
+	}
+
+	for _, e := range e.Errors {
+		if err, ok := messageToError[e]; ok {
+			errs = append(errs, err)
+		} else {
+			errs = append(errs, errors.New(e))
+		}
+	}
+
+	return errs
+}
+
+// responseErrorHandler returns an *APIError based on the HTTP status code and
+// the response body or nil if the status code is from 200 to 299.
+func responseErrorHandler(r *http.Response, method, path string, rate Rate) error {
 	if r.StatusCode/100 == 2 {
 		return nil
 	}
 
-	statusErr, ok := statusToError[r.StatusCode]
-	if !ok {
-		statusErr = errors.New("http status: " + http.StatusText(r.StatusCode))
+	apiErr := &APIError{
+		StatusCode: r.StatusCode,
+		Method:     method,
+		Path:       path,
+		Header:     r.Header,
+		Rate:       rate,
 	}
 
 	if !strings.Contains(r.Header.Get("Content-Type"), "application/json") {
-		return statusErr
-	}
-
-	errs := []error{
-		statusErr,
+		return apiErr
 	}
 
 	var e messageResponse
 	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
-		if errors.Is(err, io.EOF) { // empty body
-			return statusErr
-		}
-		errs = append(errs, fmt.Errorf("json decode: %w", err))
-	} else {
-		for _, e := range e.Errors {
-			if err, ok := messageToError[e]; ok {
-				errs = append(errs, err)
-			} else {
-				errs = append(errs, errors.New(e))
-			}
+		if !errors.Is(err, io.EOF) { // not empty body
+			apiErr.decodeErr = fmt.Errorf("json decode: %w", err)
 		}
+		return apiErr
 	}
 
-	return errors.Join(errs...)
+	apiErr.Message = e.Message
+	apiErr.Code = e.Code
+	apiErr.Errors = e.Errors
+
+	return apiErr
 }
@@ -7,6 +7,7 @@ package directdecisions_test
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 	"testing"
@@ -98,6 +99,37 @@ func TestErrors(t *testing.T) {
 	}
 }
 
+func TestAPIError(t *testing.T) {
+	client, mux, _ := newClient(t, "")
+
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172/ballots/leonardo", requireMethod("POST", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", jsonContentType)
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message": "Bad Request", "code": 400, "errors": ["Ballot Required", "Unknown Problem"]}`))
+	}))
+
+	_, err := client.Votings.Vote(context.Background(), "40f80454800b2bd7c172", "leonardo", nil)
+	assertErrors(t, err, directdecisions.ErrHTTPStatusBadRequest, directdecisions.ErrBallotRequired)
+
+	var apiErr *directdecisions.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got error %T, want %T", err, apiErr)
+	}
+
+	assertEqual(t, "status code", apiErr.StatusCode, http.StatusBadRequest)
+	assertEqual(t, "message", apiErr.Message, "Bad Request")
+	assertEqual(t, "code", apiErr.Code, 400)
+	assertEqual(t, "errors", apiErr.Errors, []string{"Ballot Required", "Unknown Problem"})
+	assertEqual(t, "method", apiErr.Method, http.MethodPost)
+	assertEqual(t, "path", apiErr.Path, "v1/votings/40f80454800b2bd7c172/ballots/leonardo")
+	assertEqual(t, "header", apiErr.Header.Get("Content-Type"), jsonContentType)
+	assertEqual(t, "rate limit", apiErr.Rate.Limit, 100)
+	assertEqual(t, "rate remaining", apiErr.Rate.Remaining, 42)
+	assertEqual(t, "error", apiErr.Error(), "http status: Bad Request\nBallot Required\nUnknown Problem")
+}
+
 func joinErrors(errors []error) string {
 	s := make([]string, len(errors))
 	for i, err := range errors {
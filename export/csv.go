@@ -0,0 +1,51 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// CSV writes results with a header row and one row per choice in the order of
+// results.
+func CSV(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"position", "choice", "index", "wins", "percentage", "strength", "advantage"})
+	for i, e := range r.Results {
+		_ = cw.Write([]string{
+			strconv.Itoa(i + 1),
+			e.Choice,
+			strconv.Itoa(e.Index),
+			strconv.Itoa(e.Wins),
+			formatPercentage(e.Percentage),
+			strconv.Itoa(e.Strength),
+			strconv.Itoa(e.Advantage),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// DuelsCSV writes duels with a header row and one row per pair of choices
+// ordered by their indexes.
+func DuelsCSV(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"left", "left_index", "left_strength", "right", "right_index", "right_strength"})
+	for _, d := range sortedDuels(r.Duels) {
+		_ = cw.Write([]string{
+			d.Left.Choice,
+			strconv.Itoa(d.Left.Index),
+			strconv.Itoa(d.Left.Strength),
+			d.Right.Choice,
+			strconv.Itoa(d.Right.Index),
+			strconv.Itoa(d.Right.Strength),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package export_test
+
+import (
+	"testing"
+
+	"directdecisions.com/directdecisions/export"
+)
+
+func TestCSV(t *testing.T) {
+	assertOutput(t, export.CSV, report, `position,choice,index,wins,percentage,strength,advantage
+1,Pepperoni,1,2,100.00,5,3
+2,Margarita,0,1,50.00,4,1
+3,Capricciosa,2,0,0.00,2,0
+`)
+}
+
+func TestDuelsCSV(t *testing.T) {
+	assertOutput(t, export.DuelsCSV, report, `left,left_index,left_strength,right,right_index,right_strength
+Margarita,0,1,Pepperoni,1,2
+Margarita,0,3,Capricciosa,2,1
+Pepperoni,1,3,Capricciosa,2,1
+`)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package export writes voting results and duels returned by directdecisions
+// VotingsService Results and Duels methods as CSV, JSON, Markdown and HTML.
+package export
+
+import (
+	"sort"
+	"strconv"
+
+	"directdecisions.com/directdecisions"
+)
+
+// Report holds the outcome of a voting.
+type Report struct {
+	VotingID string                   // Optional ID of the voting.
+	Results  []directdecisions.Result // Ranked choices.
+	Duels    []directdecisions.Duel   // Optional pairwise comparisons of choices.
+	Tie      bool                     // Whether the voting is tied.
+}
+
+// tieNotice is written for tied votings.
+const tieNotice = "The voting is tied: more than one choice has the most wins."
+
+// sortedDuels returns a copy of duels ordered by indexes of their choices.
+func sortedDuels(duels []directdecisions.Duel) []directdecisions.Duel {
+	s := append([]directdecisions.Duel(nil), duels...)
+	sort.SliceStable(s, func(i, j int) bool {
+		if s[i].Left.Index != s[j].Left.Index {
+			return s[i].Left.Index < s[j].Left.Index
+		}
+		return s[i].Right.Index < s[j].Right.Index
+	})
+	return s
+}
+
+// matrix holds numbers of voters that prefer a choice in a row over a choice
+// in a column. Choices are in the order of results.
+type matrix struct {
+	Choices []string
+	Rows    [][]matrixCell
+}
+
+type matrixCell struct {
+	Strength int
+	Self     bool // The same choice in the row and the column.
+	Wins     bool // The choice in the row is preferred by more voters.
+}
+
+// newMatrix returns the pairwise preference matrix, or nil if there are no
+// duels.
+func newMatrix(results []directdecisions.Result, duels []directdecisions.Duel) *matrix {
+	if len(duels) == 0 {
+		return nil
+	}
+	position := make(map[int]int, len(results)) // by choice index
+	m := &matrix{
+		Choices: make([]string, len(results)),
+		Rows:    make([][]matrixCell, len(results)),
+	}
+	for i, r := range results {
+		position[r.Index] = i
+		m.Choices[i] = r.Choice
+		m.Rows[i] = make([]matrixCell, len(results))
+		m.Rows[i][i].Self = true
+	}
+	for _, d := range duels {
+		l, lok := position[d.Left.Index]
+		r, rok := position[d.Right.Index]
+		if !lok || !rok {
+			continue
+		}
+		m.Rows[l][r] = matrixCell{Strength: d.Left.Strength, Wins: d.Left.Strength > d.Right.Strength}
+		m.Rows[r][l] = matrixCell{Strength: d.Right.Strength, Wins: d.Right.Strength > d.Left.Strength}
+	}
+	return m
+}
+
+func formatPercentage(p float64) string {
+	return strconv.FormatFloat(p, 'f', 2, 64)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package export_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"directdecisions.com/directdecisions"
+	"directdecisions.com/directdecisions/export"
+)
+
+var report = &export.Report{
+	VotingID: "40f80454800b2bd7c172",
+	Results: []directdecisions.Result{
+		{Choice: "Pepperoni", Index: 1, Wins: 2, Percentage: 100, Strength: 5, Advantage: 3},
+		{Choice: "Margarita", Index: 0, Wins: 1, Percentage: 50, Strength: 4, Advantage: 1},
+		{Choice: "Capricciosa", Index: 2, Wins: 0, Percentage: 0, Strength: 2, Advantage: 0},
+	},
+	Duels: []directdecisions.Duel{
+		{
+			Left:  directdecisions.ChoiceStrength{Choice: "Pepperoni", Index: 1, Strength: 3},
+			Right: directdecisions.ChoiceStrength{Choice: "Capricciosa", Index: 2, Strength: 1},
+		},
+		{
+			Left:  directdecisions.ChoiceStrength{Choice: "Margarita", Index: 0, Strength: 1},
+			Right: directdecisions.ChoiceStrength{Choice: "Pepperoni", Index: 1, Strength: 2},
+		},
+		{
+			Left:  directdecisions.ChoiceStrength{Choice: "Margarita", Index: 0, Strength: 3},
+			Right: directdecisions.ChoiceStrength{Choice: "Capricciosa", Index: 2, Strength: 1},
+		},
+	},
+}
+
+func assertOutput(t testing.TB, write func(io.Writer, *export.Report) error, r *export.Report, want string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := write(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"html/template"
+	"io"
+)
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"inc":        func(i int) int { return i + 1 },
+	"percentage": formatPercentage,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{if .VotingID}}Results of voting {{.VotingID}}{{else}}Results{{end}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ccc; padding: 0.4em 0.8em; }
+td.number { text-align: right; }
+td.wins { font-weight: bold; background: #e6f4ea; }
+td.self { background: #eee; }
+.tie { padding: 0.6em 1em; background: #fff4e5; border: 1px solid #f0b35a; }
+</style>
+</head>
+<body>
+<h1>{{if .VotingID}}Results of voting {{.VotingID}}{{else}}Results{{end}}</h1>
+{{- if .Tie}}
+<p class="tie">{{.TieNotice}}</p>
+{{- end}}
+<table>
+<thead>
+<tr><th>Position</th><th>Choice</th><th>Wins</th><th>Percentage</th><th>Strength</th><th>Advantage</th></tr>
+</thead>
+<tbody>
+{{- range $i, $r := .Results}}
+<tr><td class="number">{{inc $i}}</td><td>{{$r.Choice}}</td><td class="number">{{$r.Wins}}</td><td class="number">{{percentage $r.Percentage}}%</td><td class="number">{{$r.Strength}}</td><td class="number">{{$r.Advantage}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- with .Matrix}}
+<h2>Pairwise preferences</h2>
+<p>Number of voters that prefer the choice in the row over the choice in the column.</p>
+<table>
+<thead>
+<tr><th></th>{{range .Choices}}<th>{{.}}</th>{{end}}</tr>
+</thead>
+<tbody>
+{{- range $i, $row := .Rows}}
+<tr><th>{{index $.Matrix.Choices $i}}</th>{{range $row}}{{if .Self}}<td class="self"></td>{{else if .Wins}}<td class="number wins">{{.Strength}}</td>{{else}}<td class="number">{{.Strength}}</td>{{end}}{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+// HTML writes a self-contained HTML document with results, the tie notice for
+// tied votings and the pairwise preference matrix if the report has duels.
+func HTML(w io.Writer, r *Report) error {
+	return htmlTemplate.Execute(w, struct {
+		*Report
+		TieNotice string
+		Matrix    *matrix
+	}{
+		Report:    r,
+		TieNotice: tieNotice,
+		Matrix:    newMatrix(r.Results, r.Duels),
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package export_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"directdecisions.com/directdecisions"
+	"directdecisions.com/directdecisions/export"
+)
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := export.HTML(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"<title>Results of voting 40f80454800b2bd7c172</title>",
+		`<tr><td class="number">1</td><td>Pepperoni</td><td class="number">2</td><td class="number">100.00%</td><td class="number">5</td><td class="number">3</td></tr>`,
+		"<tr><th></th><th>Pepperoni</th><th>Margarita</th><th>Capricciosa</th></tr>",
+		`<tr><th>Margarita</th><td class="number">1</td><td class="self"></td><td class="number wins">3</td></tr>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `class="tie"`) {
+		t.Error("got unexpected tie notice")
+	}
+}
+
+func TestHTML_tieAndEscaping(t *testing.T) {
+	var buf bytes.Buffer
+	if err := export.HTML(&buf, &export.Report{
+		Results: []directdecisions.Result{
+			{Choice: "<script>alert(1)</script>"},
+		},
+		Tie: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"<title>Results</title>",
+		`<p class="tie">The voting is tied: more than one choice has the most wins.</p>`,
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Pairwise preferences") {
+		t.Error("got unexpected preference matrix")
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"directdecisions.com/directdecisions"
+)
+
+// JSON writes the report as an indented JSON object with fields in a fixed
+// order and duels ordered by indexes of their choices, so that the same
+// report is always written the same way.
+func JSON(w io.Writer, r *Report) error {
+	type jsonReport struct {
+		VotingID string                   `json:"voting_id,omitempty"`
+		Tie      bool                     `json:"tie"`
+		Results  []directdecisions.Result `json:"results"`
+		Duels    []directdecisions.Duel   `json:"duels,omitempty"`
+	}
+
+	results := r.Results
+	if results == nil {
+		results = []directdecisions.Result{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jsonReport{
+		VotingID: r.VotingID,
+		Tie:      r.Tie,
+		Results:  results,
+		Duels:    sortedDuels(r.Duels),
+	})
+}
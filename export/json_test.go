@@ -0,0 +1,68 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package export_test
+
+import (
+	"testing"
+
+	"directdecisions.com/directdecisions/export"
+)
+
+func TestJSON(t *testing.T) {
+	assertOutput(t, export.JSON, &export.Report{
+		Results: report.Results[:1],
+		Duels:   report.Duels[:2],
+		Tie:     true,
+	}, `{
+  "tie": true,
+  "results": [
+    {
+      "choice": "Pepperoni",
+      "index": 1,
+      "wins": 2,
+      "percentage": 100,
+      "strength": 5,
+      "advantage": 3
+    }
+  ],
+  "duels": [
+    {
+      "left": {
+        "choice": "Margarita",
+        "index": 0,
+        "strength": 1
+      },
+      "right": {
+        "choice": "Pepperoni",
+        "index": 1,
+        "strength": 2
+      }
+    },
+    {
+      "left": {
+        "choice": "Pepperoni",
+        "index": 1,
+        "strength": 3
+      },
+      "right": {
+        "choice": "Capricciosa",
+        "index": 2,
+        "strength": 1
+      }
+    }
+  ]
+}
+`)
+}
+
+func TestJSON_empty(t *testing.T) {
+	assertOutput(t, export.JSON, &export.Report{VotingID: "40f80454800b2bd7c172"}, `{
+  "voting_id": "40f80454800b2bd7c172",
+  "tie": false,
+  "results": []
+}
+`)
+}
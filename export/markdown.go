@@ -0,0 +1,65 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var markdownReplacer = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "*", `\*`, "_", `\_`)
+
+// Markdown writes results as a Markdown table, followed by the tie notice for
+// tied votings and the pairwise preference matrix if the report has duels.
+func Markdown(w io.Writer, r *Report) error {
+	bw := bufio.NewWriter(w)
+
+	if r.VotingID != "" {
+		fmt.Fprintf(bw, "# Results of voting %s\n\n", markdownReplacer.Replace(r.VotingID))
+	} else {
+		fmt.Fprint(bw, "# Results\n\n")
+	}
+
+	fmt.Fprintln(bw, "| Position | Choice | Wins | Percentage | Strength | Advantage |")
+	fmt.Fprintln(bw, "| ---: | --- | ---: | ---: | ---: | ---: |")
+	for i, e := range r.Results {
+		fmt.Fprintf(bw, "| %v | %s | %v | %s%% | %v | %v |\n", i+1, markdownReplacer.Replace(e.Choice), e.Wins, formatPercentage(e.Percentage), e.Strength, e.Advantage)
+	}
+
+	if r.Tie {
+		fmt.Fprintf(bw, "\n**%s**\n", tieNotice)
+	}
+
+	if m := newMatrix(r.Results, r.Duels); m != nil {
+		fmt.Fprint(bw, "\n## Pairwise preferences\n\nNumber of voters that prefer the choice in the row over the choice in the column.\n\n|  |")
+		for _, c := range m.Choices {
+			fmt.Fprintf(bw, " %s |", markdownReplacer.Replace(c))
+		}
+		fmt.Fprint(bw, "\n| --- |")
+		for range m.Choices {
+			fmt.Fprint(bw, " ---: |")
+		}
+		fmt.Fprintln(bw)
+		for i, row := range m.Rows {
+			fmt.Fprintf(bw, "| %s |", markdownReplacer.Replace(m.Choices[i]))
+			for _, cell := range row {
+				switch {
+				case cell.Self:
+					fmt.Fprint(bw, " - |")
+				case cell.Wins:
+					fmt.Fprintf(bw, " **%v** |", cell.Strength)
+				default:
+					fmt.Fprintf(bw, " %v |", cell.Strength)
+				}
+			}
+			fmt.Fprintln(bw)
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package export_test
+
+import (
+	"testing"
+
+	"directdecisions.com/directdecisions"
+	"directdecisions.com/directdecisions/export"
+)
+
+func TestMarkdown(t *testing.T) {
+	assertOutput(t, export.Markdown, report, `# Results of voting 40f80454800b2bd7c172
+
+| Position | Choice | Wins | Percentage | Strength | Advantage |
+| ---: | --- | ---: | ---: | ---: | ---: |
+| 1 | Pepperoni | 2 | 100.00% | 5 | 3 |
+| 2 | Margarita | 1 | 50.00% | 4 | 1 |
+| 3 | Capricciosa | 0 | 0.00% | 2 | 0 |
+
+## Pairwise preferences
+
+Number of voters that prefer the choice in the row over the choice in the column.
+
+|  | Pepperoni | Margarita | Capricciosa |
+| --- | ---: | ---: | ---: |
+| Pepperoni | - | **2** | **3** |
+| Margarita | 1 | - | **3** |
+| Capricciosa | 1 | 1 | - |
+`)
+}
+
+func TestMarkdown_tie(t *testing.T) {
+	assertOutput(t, export.Markdown, &export.Report{
+		Results: []directdecisions.Result{
+			{Choice: "Quattro | Formaggi", Index: 0},
+			{Choice: "Margarita", Index: 1},
+		},
+		Tie: true,
+	}, `# Results
+
+| Position | Choice | Wins | Percentage | Strength | Advantage |
+| ---: | --- | ---: | ---: | ---: | ---: |
+| 1 | Quattro \| Formaggi | 0 | 0.00% | 0 | 0 |
+| 2 | Margarita | 0 | 0.00% | 0 | 0 |
+
+**The voting is tied: more than one choice has the most wins.**
+`)
+}
@@ -5,4 +5,14 @@
 
 package directdecisions
 
+import "time"
+
 const UserAgent = userAgent
+
+func NewLimiter(r Rate) func(now time.Time) time.Duration {
+	l := new(limiter)
+	l.update(r)
+	return l.reserve
+}
+
+var PollInterval = pollInterval
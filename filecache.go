@@ -0,0 +1,107 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const fileCacheValueName = "value"
+
+// FileCache is a Cache that keeps values in files under a directory, so that
+// they can be shared between processes. Values are written atomically by
+// renaming temporary files and they expire after the maximal age.
+//
+// Errors of file system operations are not reported. Values that cannot be
+// read are treated as not found and values that cannot be written are not
+// stored.
+type FileCache struct {
+	dir    string
+	maxAge time.Duration
+}
+
+// NewFileCache returns a new FileCache that keeps values under the dir
+// directory for at most maxAge. If maxAge is zero, values do not expire.
+//
+// Cached responses may contain ballots, so the directory should be accessible
+// only by the user, such as a directory under os.UserCacheDir, and not a
+// shared one like os.TempDir.
+func NewFileCache(dir string, maxAge time.Duration) *FileCache {
+	return &FileCache{
+		dir:    dir,
+		maxAge: maxAge,
+	}
+}
+
+// Get returns the value stored under the key if it is not expired. Expired
+// values are removed.
+func (c *FileCache) Get(key string) (value []byte, ok bool) {
+	filename := filepath.Join(c.keyDir(key), fileCacheValueName)
+	if c.maxAge > 0 {
+		info, err := os.Stat(filename)
+		if err != nil {
+			return nil, false
+		}
+		if time.Since(info.ModTime()) > c.maxAge {
+			_ = os.Remove(filename)
+			return nil, false
+		}
+	}
+	value, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// Set writes the value under the key to a temporary file and renames it, so
+// that concurrent readers get either the previous or the new value.
+func (c *FileCache) Set(key string, value []byte) {
+	dir := c.keyDir(key)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return
+	}
+	f, err := os.CreateTemp(dir, "."+fileCacheValueName+"-*")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(value)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filepath.Join(dir, fileCacheValueName))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+}
+
+// Delete removes the value stored under the key and the values stored under
+// keys that start with the key followed by a slash.
+func (c *FileCache) Delete(key string) {
+	_ = os.RemoveAll(c.keyDir(key))
+}
+
+// keyDir returns the directory of the value stored under the key. Every slash
+// separated element of the key is a directory named by its hash, so that keys
+// of any length and content are valid file paths on all platforms, also on
+// case insensitive file systems.
+func (c *FileCache) keyDir(key string) string {
+	elements := strings.Split(key, "/")
+	parts := make([]string, 0, len(elements)+1)
+	parts = append(parts, c.dir)
+	for _, e := range elements {
+		h := sha256.Sum256([]byte(e))
+		parts = append(parts, hex.EncodeToString(h[:8]))
+	}
+	return filepath.Join(parts...)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"directdecisions.com/directdecisions"
+)
+
+func TestFileCache(t *testing.T) {
+	dir := t.TempDir()
+	c := directdecisions.NewFileCache(dir, 0)
+
+	_, ok := c.Get("v1/votings/a")
+	assertEqual(t, "ok", ok, false)
+
+	c.Set("v1/votings/a", []byte("voting a"))
+	c.Set("v1/votings/a/results", []byte("results a"))
+	c.Set("v1/votings/A", []byte("voting A"))
+	c.Set("v1/votings/a/ballots/"+string(make([]byte, 300)), []byte("ballot"))
+	c.Set("v1/votings/a", []byte("new voting a"))
+
+	// Values are shared between instances with the same directory.
+	c = directdecisions.NewFileCache(dir, 0)
+	for key, want := range map[string]string{
+		"v1/votings/a":         "new voting a",
+		"v1/votings/a/results": "results a",
+		"v1/votings/A":         "voting A",
+		"v1/votings/a/ballots/" + string(make([]byte, 300)): "ballot",
+	} {
+		value, ok := c.Get(key)
+		assertEqual(t, key+" ok", ok, true)
+		assertEqual(t, key, string(value), want)
+	}
+
+	c.Delete("v1/votings/a")
+	for key, want := range map[string]bool{
+		"v1/votings/a":         false,
+		"v1/votings/a/results": false,
+		"v1/votings/A":         true,
+	} {
+		_, ok := c.Get(key)
+		assertEqual(t, key, ok, want)
+	}
+
+	// Temporary files are not left behind.
+	if err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() != "value" {
+			t.Errorf("unexpected file %s", path)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileCache_maxAge(t *testing.T) {
+	dir := t.TempDir()
+	c := directdecisions.NewFileCache(dir, time.Minute)
+
+	c.Set("v1/votings/a", []byte("voting a"))
+	c.Set("v1/votings/b", []byte("voting b"))
+
+	_, ok := c.Get("v1/votings/a")
+	assertEqual(t, "ok", ok, true)
+
+	// Expire only the first value.
+	if err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		value, err := os.ReadFile(path)
+		if err != nil || string(value) != "voting a" {
+			return err
+		}
+		old := time.Now().Add(-2 * time.Minute)
+		return os.Chtimes(path, old, old)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, ok = c.Get("v1/votings/a")
+	assertEqual(t, "expired", ok, false)
+	_, ok = c.Get("v1/votings/b")
+	assertEqual(t, "not expired", ok, true)
+}
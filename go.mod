@@ -1,3 +1,3 @@
 module directdecisions.com/directdecisions
 
-go 1.20
+go 1.21
@@ -0,0 +1,112 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const (
+	headerIdempotencyKey     = "Idempotency-Key"
+	headerIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// Idempotency holds the idempotency key of a POST operation, such as
+// VotingsService Create, Set or Vote, and whether the API responded with the
+// stored response of a previous operation with the same key.
+//
+// The API performs operations with the same key only once, so an operation
+// that failed with an unknown outcome, for example with a timeout, can be
+// safely repeated with the same key.
+//
+// The Idempotency is bound to the first POST operation that uses it. Other
+// operations that are performed with the same context, for example Vote after
+// Create, do not use its key.
+type Idempotency struct {
+	// Key is the idempotency key sent with the request. If it is empty, a
+	// new random key is generated and set.
+	Key string
+	// Replayed is set to true if the response is a replay of a response to
+	// a previous request with the same key.
+	Replayed bool
+
+	operation string // operation that the key is bound to
+	path      string // request path of the bound operation
+}
+
+type idempotencyKey struct{}
+
+// ContextWithIdempotency returns a new context that makes POST operations use
+// the idempotency key from i and report the replay status in it. The same
+// Idempotency should not be used for concurrent operations, and it is not used
+// by VotingsService VoteMany and VoteStream.
+func ContextWithIdempotency(ctx context.Context, i *Idempotency) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, i)
+}
+
+// contextWithoutIdempotency returns a new context without the Idempotency
+// set by ContextWithIdempotency.
+func contextWithoutIdempotency(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(idempotencyKey{}).(*Idempotency); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, idempotencyKey{}, (*Idempotency)(nil))
+}
+
+// idempotency returns the Idempotency for the operation with the method and
+// the path, or nil if no idempotency key should be sent. The Idempotency from
+// the context is used only if it is not bound to a different operation.
+func (c *Client) idempotency(ctx context.Context, op, method, path string) (*Idempotency, error) {
+	if method != http.MethodPost {
+		return nil, nil
+	}
+	i, _ := ctx.Value(idempotencyKey{}).(*Idempotency)
+	if i != nil && i.operation == "" {
+		i.operation, i.path = op, path
+	}
+	if i == nil || i.operation != op || i.path != path {
+		if !c.idempotencyKeys {
+			return nil, nil
+		}
+		i = new(Idempotency)
+	}
+	if i.Key == "" {
+		key, err := newIdempotencyKey()
+		if err != nil {
+			return nil, err
+		}
+		i.Key = key
+	}
+	i.Replayed = false
+	return i, nil
+}
+
+// setIdempotencyKey sets the idempotency key header to the request.
+func setIdempotencyKey(r *http.Request, i *Idempotency) {
+	if i == nil {
+		return
+	}
+	r.Header.Set(headerIdempotencyKey, i.Key)
+}
+
+// setIdempotencyReplayed sets the replay status from the response.
+func setIdempotencyReplayed(i *Idempotency, r *http.Response) {
+	if i == nil || r == nil {
+		return
+	}
+	i.Replayed = r.Header.Get(headerIdempotentReplayed) == "true"
+}
+
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
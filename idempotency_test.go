@@ -0,0 +1,154 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"directdecisions.com/directdecisions"
+)
+
+func TestIdempotencyKeys(t *testing.T) {
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		IdempotencyKeys: true,
+		Retry: &directdecisions.RetryPolicy{
+			MinBackoff: time.Millisecond,
+			MaxBackoff: time.Millisecond,
+		},
+	})
+
+	var keys []string
+	mux.HandleFunc("/v1/votings", requireMethod("POST", func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		newStaticHandler(`{"id":"40f80454800b2bd7c172","choices":["Margarita"]}`)(w, r)
+	}))
+
+	var deleteKey string
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("DELETE", func(w http.ResponseWriter, r *http.Request) {
+		deleteKey = r.Header.Get("Idempotency-Key")
+	}))
+
+	// POST request with an idempotency key is retried with the same key.
+	_, err := client.Votings.Create(context.Background(), []string{"Margarita"})
+	assertErrors(t, err, nil)
+	if len(keys) != 2 {
+		t.Fatalf("got %v requests, want 2", len(keys))
+	}
+	if len(keys[0]) != 32 {
+		t.Errorf("got key %q", keys[0])
+	}
+	assertEqual(t, "retry key", keys[1], keys[0])
+
+	// The next operation gets a new key.
+	_, err = client.Votings.Create(context.Background(), []string{"Margarita"})
+	assertErrors(t, err, nil)
+	if keys[2] == keys[0] {
+		t.Errorf("got the same key %q for a new operation", keys[2])
+	}
+
+	// Keys are sent only with POST requests.
+	assertErrors(t, client.Votings.Delete(context.Background(), "40f80454800b2bd7c172"), nil)
+	assertEqual(t, "delete key", deleteKey, "")
+}
+
+func TestContextWithIdempotency(t *testing.T) {
+	client, mux, _ := newClient(t, "")
+
+	var key string
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172/ballots/leonardo", requireMethod("POST", func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get("Idempotency-Key")
+		w.Header().Set("Idempotent-Replayed", "true")
+		newStaticHandler(`{"revoted":true}`)(w, r)
+	}))
+
+	i := &directdecisions.Idempotency{Key: "job-42"}
+	revoted, err := client.Votings.Vote(directdecisions.ContextWithIdempotency(context.Background(), i), "40f80454800b2bd7c172", "leonardo", map[string]int{"Margarita": 1})
+	assertErrors(t, err, nil)
+	assertEqual(t, "revoted", revoted, true)
+	assertEqual(t, "key", key, "job-42")
+	assertEqual(t, "replayed", i.Replayed, true)
+
+	// A generated key is reported.
+	i = new(directdecisions.Idempotency)
+	_, err = client.Votings.Vote(directdecisions.ContextWithIdempotency(context.Background(), i), "40f80454800b2bd7c172", "leonardo", map[string]int{"Margarita": 1})
+	assertErrors(t, err, nil)
+	assertEqual(t, "key", key, i.Key)
+	if i.Key == "" {
+		t.Error("got empty generated key")
+	}
+}
+
+func TestContextWithIdempotency_boundOperation(t *testing.T) {
+	client, mux, _ := newClient(t, "")
+
+	var createKeys []string
+	mux.HandleFunc("/v1/votings", requireMethod("POST", func(w http.ResponseWriter, r *http.Request) {
+		createKeys = append(createKeys, r.Header.Get("Idempotency-Key"))
+		newStaticHandler(`{"id":"40f80454800b2bd7c172","choices":["Margarita"]}`)(w, r)
+	}))
+	voteKeys := make(map[string]string)
+	var voteKeysMu sync.Mutex
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172/ballots/", requireMethod("POST", func(w http.ResponseWriter, r *http.Request) {
+		voteKeysMu.Lock()
+		voteKeys[r.URL.Path] = r.Header.Get("Idempotency-Key")
+		voteKeysMu.Unlock()
+		newStaticHandler(`{"revoted":false}`)(w, r)
+	}))
+
+	i := &directdecisions.Idempotency{Key: "job-42"}
+	ctx := directdecisions.ContextWithIdempotency(context.Background(), i)
+
+	// The repeated operation uses the same key.
+	for n := 0; n < 2; n++ {
+		_, err := client.Votings.Create(ctx, []string{"Margarita"})
+		assertErrors(t, err, nil)
+	}
+	assertEqual(t, "create keys", createKeys, []string{"job-42", "job-42"})
+
+	// Other operations do not use the key.
+	_, err := client.Votings.Vote(ctx, "40f80454800b2bd7c172", "leonardo", map[string]int{"Margarita": 1})
+	assertErrors(t, err, nil)
+	assertEqual(t, "vote keys", voteKeys, map[string]string{
+		"/v1/votings/40f80454800b2bd7c172/ballots/leonardo": "",
+	})
+
+	// Concurrent votes do not use the key.
+	i = new(directdecisions.Idempotency)
+	report, err := client.Votings.VoteMany(directdecisions.ContextWithIdempotency(context.Background(), i), "40f80454800b2bd7c172", []directdecisions.VoterBallot{
+		{VoterID: "raphael", Ballot: map[string]int{"Margarita": 1}},
+		{VoterID: "donatello", Ballot: map[string]int{"Margarita": 1}},
+	}, nil)
+	assertErrors(t, err, nil)
+	assertEqual(t, "voted", report.Voted, 2)
+	assertEqual(t, "vote keys", voteKeys, map[string]string{
+		"/v1/votings/40f80454800b2bd7c172/ballots/leonardo":  "",
+		"/v1/votings/40f80454800b2bd7c172/ballots/raphael":   "",
+		"/v1/votings/40f80454800b2bd7c172/ballots/donatello": "",
+	})
+	assertEqual(t, "key", i.Key, "")
+}
+
+func TestIdempotency_disabled(t *testing.T) {
+	client, mux, _ := newClient(t, "")
+
+	key := "unset"
+	mux.HandleFunc("/v1/votings", requireMethod("POST", func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get("Idempotency-Key")
+		newStaticHandler(`{"id":"40f80454800b2bd7c172","choices":["Margarita"]}`)(w, r)
+	}))
+
+	_, err := client.Votings.Create(context.Background(), []string{"Margarita"})
+	assertErrors(t, err, nil)
+	assertEqual(t, "key", key, "")
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter delays outgoing requests based on the rate limit information
+// received in the most recent response, so that the remaining requests are
+// evenly spread until the rate limit window resets. It is safe for concurrent
+// use.
+type limiter struct {
+	mu        sync.Mutex
+	remaining int           // requests that are not yet reserved in the current window
+	reset     time.Time     // end of the current window
+	retry     time.Time     // no requests are permitted before this time
+	next      time.Time     // earliest time for the next request
+	interval  time.Duration // spacing of requests after the window resets
+}
+
+// keyLimiters holds a limiter for every API key, as the API limits the rate
+// of requests for every key separately. It is safe for concurrent use.
+type keyLimiters struct {
+	mu       sync.Mutex
+	limiters map[string]*limiter
+}
+
+// limiter returns the limiter of requests authenticated with the key.
+func (l *keyLimiters) limiter(key string) *limiter {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	k, ok := l.limiters[key]
+	if !ok {
+		if l.limiters == nil {
+			l.limiters = make(map[string]*limiter)
+		}
+		k = new(limiter)
+		l.limiters[key] = k
+	}
+	return k
+}
+
+// update sets the state of the limiter from the rate received in a response.
+func (l *limiter) update(r Rate) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if r.Limit == 0 && r.Retry.IsZero() {
+		// Response without rate limit information.
+		return
+	}
+	l.remaining = r.Remaining
+	l.reset = r.Reset
+	l.retry = r.Retry
+	if r.Limit > 0 {
+		// Requests of the next window are spread as if it starts now.
+		l.interval = time.Until(r.Reset) / time.Duration(r.Limit)
+	}
+}
+
+// wait blocks until a new request is permitted or the context is done.
+func (l *limiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	d := l.reserve(time.Now())
+	if d <= 0 {
+		return nil
+	}
+	return sleep(ctx, d)
+}
+
+// reserve returns the duration that the caller must wait before sending a
+// request at time now, and accounts the request in the current window.
+func (l *limiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	at := now
+	if l.retry.After(at) {
+		at = l.retry
+	}
+	if l.next.After(at) {
+		at = l.next
+	}
+
+	if !l.reset.After(at) {
+		// Window information is not known or it is expired. Requests are
+		// spaced until a response updates the limiter.
+		l.next = at.Add(l.interval)
+		return at.Sub(now)
+	}
+
+	if l.remaining <= 0 {
+		// Budget is exhausted, wait for the window to reset, so that the
+		// waiting requests are spaced after it.
+		l.next = l.reset.Add(l.interval)
+		return l.reset.Sub(now)
+	}
+
+	l.next = at.Add(l.reset.Sub(at) / time.Duration(l.remaining))
+	l.remaining--
+
+	return at.Sub(now)
+}
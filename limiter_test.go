@@ -0,0 +1,92 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"directdecisions.com/directdecisions"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Now()
+
+	t.Run("unknown", func(t *testing.T) {
+		reserve := directdecisions.NewLimiter(directdecisions.Rate{})
+		for i := 0; i < 3; i++ {
+			assertEqual(t, "delay", reserve(now), time.Duration(0))
+		}
+	})
+
+	t.Run("spread", func(t *testing.T) {
+		reserve := directdecisions.NewLimiter(directdecisions.Rate{
+			Limit:     10,
+			Remaining: 4,
+			Reset:     now.Add(4 * time.Second),
+		})
+		for i, want := range []time.Duration{0, time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second} {
+			if got := reserve(now); got != want {
+				t.Errorf("request %v: got delay %s, want %s", i, got, want)
+			}
+		}
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		reserve := directdecisions.NewLimiter(directdecisions.Rate{
+			Limit:     10,
+			Remaining: 0,
+			Reset:     now.Add(time.Minute),
+		})
+		assertEqual(t, "delay", reserve(now), time.Minute)
+		// Requests waiting for the reset are spaced by the limit, as if the
+		// next window has the same length.
+		for i, want := range []time.Duration{time.Minute + 6*time.Second, time.Minute + 12*time.Second} {
+			if got := reserve(now); got < want-time.Second || got > want {
+				t.Errorf("request %v: got delay %s, want %s", i, got, want)
+			}
+		}
+		if got := reserve(now.Add(2 * time.Minute)); got != 0 {
+			t.Errorf("got delay %s after the next window, want 0", got)
+		}
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		reserve := directdecisions.NewLimiter(directdecisions.Rate{
+			Retry: now.Add(30 * time.Second),
+		})
+		assertEqual(t, "delay", reserve(now), 30*time.Second)
+		assertEqual(t, "delay after retry", reserve(now.Add(30*time.Second)), time.Duration(0))
+	})
+}
+
+func TestClient_rateLimiter(t *testing.T) {
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		RateLimiter: true,
+	})
+
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("DELETE", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "10")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "1")
+	}))
+
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		assertErrors(t, client.Votings.Delete(ctx, "40f80454800b2bd7c172"), nil)
+	}
+	if d := time.Since(start); d < 500*time.Millisecond {
+		t.Errorf("got requests completed in %s, want delay until rate limit reset", d)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	assertErrors(t, client.Votings.Delete(ctx, "40f80454800b2bd7c172"), context.Canceled)
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const redacted = "REDACTED"
+
+// LogOptions configures the content of records that the Client writes to its
+// Logger.
+type LogOptions struct {
+	// Bodies enables logging of request and response headers and bodies
+	// if the Logger is enabled for the debug level.
+	Bodies bool
+	// ShowAuthorization disables redaction of the Authorization request
+	// header value.
+	ShowAuthorization bool
+	// ShowVoterIDs disables redaction of voter IDs in request paths such as
+	// v1/votings/{id}/ballots/{voterID}.
+	ShowVoterIDs bool
+}
+
+// logger writes records about API operations to a slog Logger.
+type logger struct {
+	l *slog.Logger
+	o LogOptions
+}
+
+func newLogger(l *slog.Logger, o *LogOptions) *logger {
+	if l == nil {
+		return nil
+	}
+	if o == nil {
+		o = new(LogOptions)
+	}
+	return &logger{
+		l: l,
+		o: *o,
+	}
+}
+
+// logBodies returns true if request and response bodies should be included in
+// log records.
+func (l *logger) logBodies(ctx context.Context) bool {
+	return l != nil && l.o.Bodies && l.l.Enabled(ctx, slog.LevelDebug)
+}
+
+// log writes a single record about the API operation with the last call made
+// for it and the error that the operation returned.
+func (l *logger) log(ctx context.Context, op, method, path string, call *Call, requestBody []byte, d time.Duration, err error) {
+	if l == nil {
+		return
+	}
+
+	bodies := l.logBodies(ctx)
+	level := slog.LevelInfo
+	switch {
+	case err != nil:
+		level = slog.LevelError
+	case bodies:
+		level = slog.LevelDebug
+	}
+	if !l.l.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", op),
+		slog.String("method", method),
+		slog.String("path", l.redactPath(path)),
+		slog.Duration("duration", d),
+	}
+
+	if call != nil {
+		attrs = append(attrs, slog.Int("attempts", call.sent))
+		if call.cacheHit {
+			attrs = append(attrs, slog.Bool("cached", true))
+		}
+		if status := callStatus(call, err); status != 0 {
+			attrs = append(attrs, slog.Int("status", status))
+		}
+		if call.rate.Limit != 0 {
+			attrs = append(attrs, slog.Int("rate_remaining", call.rate.Remaining))
+		}
+	}
+
+	if err != nil {
+		attrs = append(attrs,
+			slog.String("error", l.errorMessage(err, path)),
+			slog.String("error_class", errorClass(err)),
+		)
+	}
+
+	if bodies {
+		if call != nil {
+			attrs = append(attrs, slog.Any("request_headers", l.headers(call.Request.Header)))
+		}
+		if requestBody != nil {
+			attrs = append(attrs, slog.String("request_body", strings.TrimSpace(string(requestBody))))
+		}
+		if call != nil && call.Response != nil {
+			attrs = append(attrs,
+				slog.Any("response_headers", l.headers(call.Response.Header)),
+				slog.String("response_body", strings.TrimSpace(string(call.responseBody))),
+			)
+		}
+	}
+
+	l.l.LogAttrs(ctx, level, "directdecisions api request", attrs...)
+}
+
+// headers returns a copy of HTTP headers with redacted sensitive values.
+func (l *logger) headers(h http.Header) http.Header {
+	h = h.Clone()
+	if !l.o.ShowAuthorization && h.Get("Authorization") != "" {
+		h.Set("Authorization", redacted)
+	}
+	return h
+}
+
+// redactPath returns the request path with the voter ID redacted, unless
+// voter IDs should be shown.
+func (l *logger) redactPath(path string) string {
+	if l.o.ShowVoterIDs {
+		return path
+	}
+	return redactVoterID(path)
+}
+
+// errorMessage returns the message of the error with the voter ID redacted
+// from the request path, which is contained in *url.Error transport errors.
+func (l *logger) errorMessage(err error, path string) string {
+	msg := err.Error()
+	redactedPath := l.redactPath(path)
+	if redactedPath == path {
+		return msg
+	}
+	msg = strings.ReplaceAll(msg, path, redactedPath)
+	if unescaped, err := url.PathUnescape(path); err == nil && unescaped != path {
+		msg = strings.ReplaceAll(msg, unescaped, redactedPath)
+	}
+	return msg
+}
+
+// callStatus returns the HTTP status code of the call response or the status
+// code of the APIError, if the response was not received.
+func callStatus(call *Call, err error) int {
+	if call.cacheHit {
+		return 0
+	}
+	if call.Response != nil {
+		return call.Response.StatusCode
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// redactVoterID replaces the voter ID in the ballots path with a placeholder.
+func redactVoterID(path string) string {
+	// v1/votings/{id}/ballots/{voterID}
+	parts := strings.Split(path, "/")
+	if len(parts) == 5 && parts[3] == "ballots" {
+		parts[4] = redacted
+		return strings.Join(parts, "/")
+	}
+	return path
+}
+
+// errorClass returns a short description of the kind of the error returned by
+// an API operation.
+func errorClass(err error) string {
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, ErrCircuitOpen):
+		return "circuit_open"
+	case errors.As(err, &apiErr):
+		switch {
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			return "rate_limited"
+		case apiErr.StatusCode == http.StatusUnauthorized, apiErr.StatusCode == http.StatusForbidden:
+			return "unauthorized"
+		case apiErr.StatusCode == http.StatusNotFound:
+			return "not_found"
+		case apiErr.StatusCode/100 == 4:
+			return "invalid_request"
+		default:
+			return "server_error"
+		}
+	case isValidationError(err):
+		return "invalid_request"
+	}
+	return "transport"
+}
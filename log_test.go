@@ -0,0 +1,192 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"directdecisions.com/directdecisions"
+)
+
+func TestLogger(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		level   slog.Level
+		options *directdecisions.LogOptions
+		want    map[string]any
+		absent  []string
+	}{
+		{
+			name:  "default",
+			level: slog.LevelInfo,
+			want: map[string]any{
+				"level":          "ERROR",
+				"operation":      "Votings.Vote",
+				"method":         "POST",
+				"path":           "v1/votings/40f80454800b2bd7c172/ballots/REDACTED",
+				"status":         float64(http.StatusBadRequest),
+				"attempts":       float64(1),
+				"rate_remaining": float64(41),
+				"error_class":    "invalid_request",
+				"error":          "http status: Bad Request\nBallot Required",
+			},
+			absent: []string{"request_body", "response_body", "request_headers"},
+		},
+		{
+			name:    "bodies without debug level",
+			level:   slog.LevelInfo,
+			options: &directdecisions.LogOptions{Bodies: true},
+			want: map[string]any{
+				"level": "ERROR",
+			},
+			absent: []string{"request_body", "response_body", "request_headers"},
+		},
+		{
+			name:    "bodies",
+			level:   slog.LevelDebug,
+			options: &directdecisions.LogOptions{Bodies: true},
+			want: map[string]any{
+				"level":         "ERROR",
+				"path":          "v1/votings/40f80454800b2bd7c172/ballots/REDACTED",
+				"request_body":  `{"ballot":{"Margarita":0}}`,
+				"response_body": `{"message": "Bad Request", "code": 400, "errors": ["Ballot Required"]}`,
+				"request_headers": map[string]any{
+					"Accept":        []any{jsonContentType},
+					"Authorization": []any{"REDACTED"},
+					"Content-Type":  []any{jsonContentType},
+				},
+			},
+		},
+		{
+			name:  "show sensitive values",
+			level: slog.LevelDebug,
+			options: &directdecisions.LogOptions{
+				Bodies:            true,
+				ShowAuthorization: true,
+				ShowVoterIDs:      true,
+			},
+			want: map[string]any{
+				"path": "v1/votings/40f80454800b2bd7c172/ballots/leonardo",
+				"request_headers": map[string]any{
+					"Accept":        []any{jsonContentType},
+					"Authorization": []any{"Bearer my-key"},
+					"Content-Type":  []any{jsonContentType},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			client, mux, _ := newClientWithOptions(t, "my-key", &directdecisions.ClientOptions{
+				Logger:     slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: tc.level})),
+				LogOptions: tc.options,
+			})
+
+			mux.HandleFunc("/v1/votings/40f80454800b2bd7c172/ballots/leonardo", requireMethod("POST", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", jsonContentType)
+				w.Header().Set("X-RateLimit-Limit", "100")
+				w.Header().Set("X-RateLimit-Remaining", "41")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"message": "Bad Request", "code": 400, "errors": ["Ballot Required"]}`))
+			}))
+
+			_, err := client.Votings.Vote(context.Background(), "40f80454800b2bd7c172", "leonardo", map[string]int{"Margarita": 0})
+			assertErrors(t, err, directdecisions.ErrBallotRequired)
+
+			var records []map[string]any
+			dec := json.NewDecoder(&buf)
+			for dec.More() {
+				var r map[string]any
+				if err := dec.Decode(&r); err != nil {
+					t.Fatal(err)
+				}
+				records = append(records, r)
+			}
+
+			if len(records) != 1 {
+				t.Fatalf("got %v log records, want 1", len(records))
+			}
+			record := records[0]
+
+			for k, v := range tc.want {
+				assertEqual(t, k, record[k], v)
+			}
+			for _, k := range tc.absent {
+				if _, ok := record[k]; ok {
+					t.Errorf("got unexpected %q attribute", k)
+				}
+			}
+			if _, ok := record["duration"]; !ok {
+				t.Error("missing duration attribute")
+			}
+		})
+	}
+}
+
+func TestLogger_transportError(t *testing.T) {
+	var buf bytes.Buffer
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	baseURL, err := url.Parse(server.URL)
+	assertErrors(t, err, nil)
+	server.Close()
+
+	client := directdecisions.NewClient("", &directdecisions.ClientOptions{
+		BaseURL: baseURL,
+		Logger:  slog.New(slog.NewJSONHandler(&buf, nil)),
+	})
+
+	_, err = client.Votings.Vote(context.Background(), "40f80454800b2bd7c172", "leo nardo", map[string]int{"Margarita": 1})
+	if err == nil || !strings.Contains(err.Error(), "leo%20nardo") {
+		t.Fatalf("got error %v", err)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, "error_class", record["error_class"], "transport")
+	msg, _ := record["error"].(string)
+	if strings.Contains(msg, "leo") || !strings.Contains(msg, "v1/votings/40f80454800b2bd7c172/ballots/REDACTED") {
+		t.Errorf("got error attribute %q", msg)
+	}
+}
+
+func TestLogger_success(t *testing.T) {
+	var buf bytes.Buffer
+
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Logger: slog.New(slog.NewJSONHandler(&buf, nil)),
+	})
+
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("DELETE", func(w http.ResponseWriter, r *http.Request) {}))
+
+	assertErrors(t, client.Votings.Delete(context.Background(), "40f80454800b2bd7c172"), nil)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, "level", record["level"], "INFO")
+	assertEqual(t, "operation", record["operation"], "Votings.Delete")
+	assertEqual(t, "path", record["path"], "v1/votings/40f80454800b2bd7c172")
+	assertEqual(t, "status", record["status"], float64(http.StatusOK))
+	assertEqual(t, "attempts", record["attempts"], float64(1))
+	if _, ok := record["error"]; ok {
+		t.Error("got unexpected error attribute")
+	}
+}
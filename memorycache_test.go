@@ -0,0 +1,56 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"testing"
+
+	"directdecisions.com/directdecisions"
+)
+
+func TestMemoryCache(t *testing.T) {
+	c := directdecisions.NewMemoryCache(3)
+
+	c.Set("v1/votings/a", []byte("voting a"))
+	c.Set("v1/votings/a/results", []byte("results a"))
+	c.Set("v1/votings/ab", []byte("voting ab"))
+
+	value, ok := c.Get("v1/votings/a")
+	assertEqual(t, "ok", ok, true)
+	assertEqual(t, "value", string(value), "voting a")
+
+	// The least recently used value is removed.
+	c.Set("v1/votings/b", []byte("voting b"))
+	assertEqual(t, "len", c.Len(), 3)
+	_, ok = c.Get("v1/votings/a/results")
+	assertEqual(t, "evicted", ok, false)
+
+	c.Set("v1/votings/a", []byte("new voting a"))
+	value, ok = c.Get("v1/votings/a")
+	assertEqual(t, "ok", ok, true)
+	assertEqual(t, "value", string(value), "new voting a")
+	assertEqual(t, "len", c.Len(), 3)
+}
+
+func TestMemoryCache_Delete(t *testing.T) {
+	c := directdecisions.NewMemoryCache(0)
+
+	c.Set("v1/votings/a", []byte("voting a"))
+	c.Set("v1/votings/a/results", []byte("results a"))
+	c.Set("v1/votings/ab", []byte("voting ab"))
+
+	// Deletion removes values under the key, but not the ones that only
+	// share the prefix.
+	c.Delete("v1/votings/a")
+	for key, want := range map[string]bool{
+		"v1/votings/a":         false,
+		"v1/votings/a/results": false,
+		"v1/votings/ab":        true,
+	} {
+		_, ok := c.Get(key)
+		assertEqual(t, key, ok, want)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import (
+	"errors"
+	"time"
+)
+
+// Metrics receives measurements of API operations performed by the Client.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveOperation is called when an API operation ends.
+	ObserveOperation(o OperationMeasurement)
+	// ObserveRate is called with the rate limit information from every API
+	// response.
+	ObserveRate(r Rate)
+}
+
+// OperationMeasurement holds measurements of a single API operation.
+type OperationMeasurement struct {
+	Operation  string        // Name of the API operation, such as "Votings.Vote".
+	StatusCode int           // HTTP status code of the last response, or zero if it is not received.
+	Duration   time.Duration // Duration of the operation including all attempts.
+	Attempts   int           // Number of sent HTTP requests.
+	Cached     bool          // The response is served from the cache without sending a request.
+	Err        error         // Error returned by the operation.
+}
+
+// observe reports the ended operation to the Client's metrics.
+func (c *Client) observe(op string, call *Call, d time.Duration, err error) {
+	if c.metrics == nil {
+		return
+	}
+	m := OperationMeasurement{
+		Operation: op,
+		Duration:  d,
+		Err:       err,
+	}
+	if call != nil {
+		m.Attempts = call.sent
+		m.StatusCode = callStatus(call, err)
+		m.Cached = call.cacheHit
+	}
+	c.metrics.ObserveOperation(m)
+}
+
+// observeRate reports the rate limit information to the Client's metrics.
+func (c *Client) observeRate(r Rate) {
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.ObserveRate(r)
+}
+
+// sentinelErrors are errors that can be matched with errors.Is with their
+// variable names.
+var sentinelErrors = []struct {
+	err  error
+	name string
+}{
+	{ErrHTTPStatusBadRequest, "ErrHTTPStatusBadRequest"},
+	{ErrHTTPStatusUnauthorized, "ErrHTTPStatusUnauthorized"},
+	{ErrHTTPStatusForbidden, "ErrHTTPStatusForbidden"},
+	{ErrHTTPStatusNotFound, "ErrHTTPStatusNotFound"},
+	{ErrHTTPStatusMethodNotAllowed, "ErrHTTPStatusMethodNotAllowed"},
+	{ErrHTTPStatusTooManyRequests, "ErrHTTPStatusTooManyRequests"},
+	{ErrHTTPStatusInternalServerError, "ErrHTTPStatusInternalServerError"},
+	{ErrHTTPStatusServiceUnavailable, "ErrHTTPStatusServiceUnavailable"},
+	{ErrHTTPStatusBadGateway, "ErrHTTPStatusBadGateway"},
+	{ErrInvalidData, "ErrInvalidData"},
+	{ErrMissingChoices, "ErrMissingChoices"},
+	{ErrChoiceRequired, "ErrChoiceRequired"},
+	{ErrChoiceTooLong, "ErrChoiceTooLong"},
+	{ErrTooManyChoices, "ErrTooManyChoices"},
+	{ErrBallotRequired, "ErrBallotRequired"},
+	{ErrVoterIDTooLong, "ErrVoterIDTooLong"},
+	{ErrInvalidVoterID, "ErrInvalidVoterID"},
+	{ErrCircuitOpen, "ErrCircuitOpen"},
+	{ErrNoResponse, "ErrNoResponse"},
+	{ErrNoKeys, "ErrNoKeys"},
+}
+
+// errorNames returns variable names of all sentinel errors that the error
+// matches, or its error class if it does not match any.
+func errorNames(err error) (names []string) {
+	if err == nil {
+		return nil
+	}
+	for _, s := range sentinelErrors {
+		if errors.Is(err, s.err) {
+			names = append(names, s.name)
+		}
+	}
+	if len(names) == 0 {
+		names = append(names, errorClass(err))
+	}
+	return names
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"directdecisions.com/directdecisions"
+)
+
+type recordingMetrics struct {
+	mu         sync.Mutex
+	operations []directdecisions.OperationMeasurement
+	rates      []directdecisions.Rate
+}
+
+func (m *recordingMetrics) ObserveOperation(o directdecisions.OperationMeasurement) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.operations = append(m.operations, o)
+}
+
+func (m *recordingMetrics) ObserveRate(r directdecisions.Rate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rates = append(m.rates, r)
+}
+
+func TestMetrics(t *testing.T) {
+	metrics := new(recordingMetrics)
+
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Metrics: metrics,
+	})
+
+	mux.HandleFunc("/v1/votings", requireMethod("POST", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", jsonContentType)
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "99")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message": "Bad Request", "code": 400, "errors": ["Choice Too Long"]}`))
+	}))
+
+	_, err := client.Votings.Create(context.Background(), []string{"Margarita"})
+	assertErrors(t, err, directdecisions.ErrChoiceTooLong)
+
+	if len(metrics.operations) != 1 {
+		t.Fatalf("got %v operations, want 1", len(metrics.operations))
+	}
+	o := metrics.operations[0]
+	assertEqual(t, "operation", o.Operation, "Votings.Create")
+	assertEqual(t, "status code", o.StatusCode, http.StatusBadRequest)
+	assertEqual(t, "attempts", o.Attempts, 1)
+	assertErrors(t, o.Err, directdecisions.ErrChoiceTooLong)
+	if o.Duration <= 0 {
+		t.Errorf("got duration %s", o.Duration)
+	}
+
+	if len(metrics.rates) != 1 {
+		t.Fatalf("got %v rates, want 1", len(metrics.rates))
+	}
+	assertEqual(t, "rate limit", metrics.rates[0].Limit, 100)
+	assertEqual(t, "rate remaining", metrics.rates[0].Remaining, 99)
+}
+
+func TestMetrics_cached(t *testing.T) {
+	metrics := new(recordingMetrics)
+
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Metrics: metrics,
+		Cache:   &directdecisions.CacheOptions{TTL: time.Hour},
+	})
+
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("GET", newStaticHandler(`{"id":"40f80454800b2bd7c172","choices":["Margarita","Diavola","Capricciosa"]}`)))
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Votings.Voting(context.Background(), "40f80454800b2bd7c172")
+		assertErrors(t, err, nil)
+	}
+
+	if len(metrics.operations) != 2 {
+		t.Fatalf("got %v operations, want 2", len(metrics.operations))
+	}
+	o := metrics.operations[0]
+	assertEqual(t, "status code", o.StatusCode, http.StatusOK)
+	assertEqual(t, "attempts", o.Attempts, 1)
+	assertEqual(t, "cached", o.Cached, false)
+
+	// Responses served from the cache are not counted as sent requests.
+	o = metrics.operations[1]
+	assertEqual(t, "status code", o.StatusCode, 0)
+	assertEqual(t, "attempts", o.Attempts, 0)
+	assertEqual(t, "cached", o.Cached, true)
+}
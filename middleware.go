@@ -0,0 +1,56 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import "net/http"
+
+// Call holds information about a single HTTP request that the Client sends to
+// the API. If a request is retried, every attempt is a separate Call.
+type Call struct {
+	// Operation is the name of the API operation, such as "Votings.Vote".
+	Operation string
+	// Attempt is the number of the attempt for the operation, starting
+	// from 1.
+	Attempt int
+	// Request is the outgoing HTTP request with the URL relative to the API
+	// base URL. User-Agent header and the base URL are applied by the
+	// Client's HTTP transport after all middleware.
+	Request *http.Request
+	// Response is the received HTTP response. It is nil until the Handler
+	// that sends the request returns, or if the response is not received.
+	// Its body is closed by the Client.
+	Response *http.Response
+
+	path         string
+	sent         int    // number of requests sent by the operation so far
+	cacheHit     bool   // response is served from the cache without a request
+	key          string // API key that authenticates the request
+	rate         Rate
+	responseBody []byte      // set only if response bodies are logged
+	cached       *cacheEntry // cached response to the GET request
+	cacheKey     string      // key of the cached response
+}
+
+// Handler sends the HTTP request of the Call, sets the Call Response and
+// returns the error decoded from the response, which is an *APIError if the
+// API responded with a status code that is not from 200 to 299.
+type Handler func(call *Call) error
+
+// Middleware wraps a Handler to intercept API calls. It may inspect or modify
+// the Call Request before calling the next Handler, inspect the Call Response
+// and the returned error after it, or return without calling it at all. A
+// Middleware that returns a nil error without calling the next Handler must
+// set the Call Response, otherwise the operation fails with ErrNoResponse.
+type Middleware func(next Handler) Handler
+
+// chain wraps the Handler with the middleware so that the first one in the
+// list is the outermost one.
+func chain(h Handler, middleware []Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+	return h
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"directdecisions.com/directdecisions"
+)
+
+func TestMiddleware(t *testing.T) {
+	var calls []string
+
+	record := func(name string) directdecisions.Middleware {
+		return func(next directdecisions.Handler) directdecisions.Handler {
+			return func(call *directdecisions.Call) error {
+				calls = append(calls, name+" before "+call.Operation)
+				err := next(call)
+				status := 0
+				if call.Response != nil {
+					status = call.Response.StatusCode
+				}
+				calls = append(calls, name+" after "+http.StatusText(status))
+				if err != nil {
+					calls = append(calls, name+" error "+err.Error())
+				}
+				return err
+			}
+		}
+	}
+
+	injectHeader := func(next directdecisions.Handler) directdecisions.Handler {
+		return func(call *directdecisions.Call) error {
+			call.Request.Header.Set("X-Request-ID", "request-1")
+			return next(call)
+		}
+	}
+
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Middleware: []directdecisions.Middleware{
+			record("first"),
+			injectHeader,
+			record("second"),
+		},
+	})
+
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172/ballots/leonardo", requireMethod("DELETE", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Request-ID") != "request-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	err := client.Votings.Unvote(context.Background(), "40f80454800b2bd7c172", "leonardo")
+	assertErrors(t, err, directdecisions.ErrHTTPStatusNotFound)
+
+	assertEqual(t, "calls", calls, []string{
+		"first before Votings.Unvote",
+		"second before Votings.Unvote",
+		"second after Not Found",
+		"second error http status: Not Found",
+		"first after Not Found",
+		"first error http status: Not Found",
+	})
+}
+
+func TestMiddleware_faultInjection(t *testing.T) {
+	var attempts []int
+
+	faulty := func(next directdecisions.Handler) directdecisions.Handler {
+		return func(call *directdecisions.Call) error {
+			attempts = append(attempts, call.Attempt)
+			if call.Attempt == 1 {
+				return &directdecisions.APIError{
+					StatusCode: http.StatusServiceUnavailable,
+					Method:     call.Request.Method,
+				}
+			}
+			return next(call)
+		}
+	}
+
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Retry:      &directdecisions.RetryPolicy{MinBackoff: time.Millisecond},
+		Middleware: []directdecisions.Middleware{faulty},
+	})
+
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("GET", newStaticHandler(`{
+		"id": "40f80454800b2bd7c172",
+		"choices": ["Margarita", "Diavola", "Capricciosa"]
+	}`)))
+
+	got, err := client.Votings.Voting(context.Background(), "40f80454800b2bd7c172")
+	assertErrors(t, err, nil)
+
+	assertEqual(t, "voting", got, votingsServiceVotingWant)
+	assertEqual(t, "attempts", attempts, []int{1, 2})
+}
+
+func TestMiddleware_shortCircuit(t *testing.T) {
+	errInjected := errors.New("injected")
+
+	client, _, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Middleware: []directdecisions.Middleware{
+			func(next directdecisions.Handler) directdecisions.Handler {
+				return func(call *directdecisions.Call) error {
+					return errInjected
+				}
+			},
+		},
+	})
+
+	_, err := client.Votings.Create(context.Background(), []string{"Margarita", "Diavola"})
+	assertErrors(t, err, errInjected)
+}
+
+func TestMiddleware_shortCircuitWithoutResponse(t *testing.T) {
+	client, _, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Middleware: []directdecisions.Middleware{
+			func(next directdecisions.Handler) directdecisions.Handler {
+				return func(call *directdecisions.Call) error {
+					return nil
+				}
+			},
+		},
+	})
+
+	_, err := client.Votings.Set(context.Background(), "40f80454800b2bd7c172", "Margarita", 0)
+	if err == nil {
+		t.Fatal("got no error")
+	}
+	assertErrors(t, err, directdecisions.ErrNoResponse)
+}
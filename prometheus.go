@@ -0,0 +1,232 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultDurationBuckets are upper bounds in seconds of operation duration
+// histogram buckets used by PrometheusMetrics.
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PrometheusMetrics is a Metrics implementation that keeps measurements in
+// memory and exposes them in the Prometheus text exposition format. It is an
+// http.Handler that can be registered on a metrics endpoint.
+//
+// The following metrics are exposed:
+//
+//   - directdecisions_requests_total counter of API operations by operation
+//     and status, which is the HTTP status code, "cached" for responses
+//     served from the cache, or "error" if no response is received,
+//   - directdecisions_request_duration_seconds histogram of API operation
+//     durations by operation,
+//   - directdecisions_request_attempts_total counter of sent HTTP requests by
+//     operation,
+//   - directdecisions_errors_total counter of API operation errors by
+//     operation and error, which is the name of the sentinel error, such as
+//     ErrChoiceTooLong, or the kind of the error if it does not match any,
+//   - directdecisions_rate_limit_limit and directdecisions_rate_limit_remaining
+//     gauges with the most recent rate limit information.
+type PrometheusMetrics struct {
+	buckets []float64
+
+	mu            sync.Mutex
+	requests      map[[2]string]uint64 // by operation and status
+	attempts      map[string]uint64    // by operation
+	errors        map[[2]string]uint64 // by operation and error
+	durations     map[string]*histogram
+	rateLimit     int
+	rateRemaining int
+	rateObserved  bool
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// NewPrometheusMetrics returns a new PrometheusMetrics with duration histogram
+// buckets upper bounds in seconds. If buckets are nil, DefaultDurationBuckets
+// are used.
+func NewPrometheusMetrics(buckets []float64) *PrometheusMetrics {
+	if buckets == nil {
+		buckets = DefaultDurationBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &PrometheusMetrics{
+		buckets:   buckets,
+		requests:  make(map[[2]string]uint64),
+		attempts:  make(map[string]uint64),
+		errors:    make(map[[2]string]uint64),
+		durations: make(map[string]*histogram),
+	}
+}
+
+// ObserveOperation implements the Metrics interface.
+func (m *PrometheusMetrics) ObserveOperation(o OperationMeasurement) {
+	status := "error"
+	switch {
+	case o.Cached:
+		status = "cached"
+	case o.StatusCode != 0:
+		status = strconv.Itoa(o.StatusCode)
+	}
+	names := errorNames(o.Err)
+	seconds := o.Duration.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[[2]string{o.Operation, status}]++
+	m.attempts[o.Operation] += uint64(o.Attempts)
+	for _, n := range names {
+		m.errors[[2]string{o.Operation, n}]++
+	}
+
+	h, ok := m.durations[o.Operation]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.durations[o.Operation] = h
+	}
+	for i, b := range m.buckets {
+		if seconds <= b {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// ObserveRate implements the Metrics interface.
+func (m *PrometheusMetrics) ObserveRate(r Rate) {
+	if r.Limit == 0 {
+		// Response without rate limit information.
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rateLimit = r.Limit
+	m.rateRemaining = r.Remaining
+	m.rateObserved = true
+}
+
+// ServeHTTP writes all metrics in the Prometheus text exposition format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format to the
+// writer.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (n int64, err error) {
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	m.mu.Lock()
+
+	writeHeader(bw, "directdecisions_requests_total", "counter", "Total number of API operations.")
+	for _, k := range sortedPairs(m.requests) {
+		fmt.Fprintf(bw, "directdecisions_requests_total{operation=%s,status=%s} %v\n", quote(k[0]), quote(k[1]), m.requests[k])
+	}
+
+	writeHeader(bw, "directdecisions_request_attempts_total", "counter", "Total number of HTTP requests sent for API operations.")
+	for _, op := range sortedKeys(m.attempts) {
+		fmt.Fprintf(bw, "directdecisions_request_attempts_total{operation=%s} %v\n", quote(op), m.attempts[op])
+	}
+
+	writeHeader(bw, "directdecisions_errors_total", "counter", "Total number of API operation errors.")
+	for _, k := range sortedPairs(m.errors) {
+		fmt.Fprintf(bw, "directdecisions_errors_total{operation=%s,error=%s} %v\n", quote(k[0]), quote(k[1]), m.errors[k])
+	}
+
+	writeHeader(bw, "directdecisions_request_duration_seconds", "histogram", "Duration of API operations in seconds.")
+	for _, op := range sortedKeys(m.durations) {
+		h := m.durations[op]
+		var cumulative uint64
+		for i, b := range m.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(bw, "directdecisions_request_duration_seconds_bucket{operation=%s,le=%s} %v\n", quote(op), quote(formatFloat(b)), cumulative)
+		}
+		fmt.Fprintf(bw, "directdecisions_request_duration_seconds_bucket{operation=%s,le=\"+Inf\"} %v\n", quote(op), h.count)
+		fmt.Fprintf(bw, "directdecisions_request_duration_seconds_sum{operation=%s} %s\n", quote(op), formatFloat(h.sum))
+		fmt.Fprintf(bw, "directdecisions_request_duration_seconds_count{operation=%s} %v\n", quote(op), h.count)
+	}
+
+	if m.rateObserved {
+		writeHeader(bw, "directdecisions_rate_limit_limit", "gauge", "Maximal number of API requests in the rate limit window.")
+		fmt.Fprintf(bw, "directdecisions_rate_limit_limit %v\n", m.rateLimit)
+		writeHeader(bw, "directdecisions_rate_limit_remaining", "gauge", "Number of API requests remaining in the current rate limit window.")
+		fmt.Fprintf(bw, "directdecisions_rate_limit_remaining %v\n", m.rateRemaining)
+	}
+
+	m.mu.Unlock()
+
+	err = bw.Flush()
+	return cw.n, err
+}
+
+func writeHeader(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quote returns the label value in double quotes with escaped characters.
+func quote(s string) string {
+	return `"` + labelValueReplacer.Replace(s) + `"`
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedPairs(m map[[2]string]uint64) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
+
+// countWriter counts the number of bytes written to the underlying writer.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (n int, err error) {
+	n, err = w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
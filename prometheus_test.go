@@ -0,0 +1,124 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"directdecisions.com/directdecisions"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	m := directdecisions.NewPrometheusMetrics([]float64{0.1, 1})
+
+	m.ObserveOperation(directdecisions.OperationMeasurement{
+		Operation:  "Votings.Vote",
+		StatusCode: http.StatusOK,
+		Duration:   50 * time.Millisecond,
+		Attempts:   1,
+	})
+	m.ObserveOperation(directdecisions.OperationMeasurement{
+		Operation:  "Votings.Vote",
+		StatusCode: http.StatusBadRequest,
+		Duration:   500 * time.Millisecond,
+		Attempts:   1,
+		Err: &directdecisions.APIError{
+			StatusCode: http.StatusBadRequest,
+			Errors:     []string{"Choice Too Long"},
+		},
+	})
+	m.ObserveOperation(directdecisions.OperationMeasurement{
+		Operation: "Votings.Results",
+		Duration:  2 * time.Second,
+		Attempts:  3,
+		Err:       errors.New("connection refused"),
+	})
+	m.ObserveOperation(directdecisions.OperationMeasurement{
+		Operation: "Votings.Results",
+		Cached:    true,
+	})
+	m.ObserveRate(directdecisions.Rate{Limit: 100, Remaining: 42})
+
+	r := httptest.NewRecorder()
+	m.ServeHTTP(r, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assertEqual(t, "content type", r.Header().Get("Content-Type"), "text/plain; version=0.0.4; charset=utf-8")
+	assertEqual(t, "body", r.Body.String(), `# HELP directdecisions_requests_total Total number of API operations.
+# TYPE directdecisions_requests_total counter
+directdecisions_requests_total{operation="Votings.Results",status="cached"} 1
+directdecisions_requests_total{operation="Votings.Results",status="error"} 1
+directdecisions_requests_total{operation="Votings.Vote",status="200"} 1
+directdecisions_requests_total{operation="Votings.Vote",status="400"} 1
+# HELP directdecisions_request_attempts_total Total number of HTTP requests sent for API operations.
+# TYPE directdecisions_request_attempts_total counter
+directdecisions_request_attempts_total{operation="Votings.Results"} 3
+directdecisions_request_attempts_total{operation="Votings.Vote"} 2
+# HELP directdecisions_errors_total Total number of API operation errors.
+# TYPE directdecisions_errors_total counter
+directdecisions_errors_total{operation="Votings.Results",error="transport"} 1
+directdecisions_errors_total{operation="Votings.Vote",error="ErrChoiceTooLong"} 1
+directdecisions_errors_total{operation="Votings.Vote",error="ErrHTTPStatusBadRequest"} 1
+# HELP directdecisions_request_duration_seconds Duration of API operations in seconds.
+# TYPE directdecisions_request_duration_seconds histogram
+directdecisions_request_duration_seconds_bucket{operation="Votings.Results",le="0.1"} 1
+directdecisions_request_duration_seconds_bucket{operation="Votings.Results",le="1"} 1
+directdecisions_request_duration_seconds_bucket{operation="Votings.Results",le="+Inf"} 2
+directdecisions_request_duration_seconds_sum{operation="Votings.Results"} 2
+directdecisions_request_duration_seconds_count{operation="Votings.Results"} 2
+directdecisions_request_duration_seconds_bucket{operation="Votings.Vote",le="0.1"} 1
+directdecisions_request_duration_seconds_bucket{operation="Votings.Vote",le="1"} 2
+directdecisions_request_duration_seconds_bucket{operation="Votings.Vote",le="+Inf"} 2
+directdecisions_request_duration_seconds_sum{operation="Votings.Vote"} 0.55
+directdecisions_request_duration_seconds_count{operation="Votings.Vote"} 2
+# HELP directdecisions_rate_limit_limit Maximal number of API requests in the rate limit window.
+# TYPE directdecisions_rate_limit_limit gauge
+directdecisions_rate_limit_limit 100
+# HELP directdecisions_rate_limit_remaining Number of API requests remaining in the current rate limit window.
+# TYPE directdecisions_rate_limit_remaining gauge
+directdecisions_rate_limit_remaining 42
+`)
+}
+
+func TestPrometheusMetrics_client(t *testing.T) {
+	m := directdecisions.NewPrometheusMetrics(nil)
+
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Metrics: m,
+	})
+
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "10")
+		w.Header().Set("X-RateLimit-Remaining", "9")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+
+	_, err := client.Votings.Voting(context.Background(), "40f80454800b2bd7c172")
+	assertErrors(t, err, directdecisions.ErrHTTPStatusTooManyRequests)
+
+	var b strings.Builder
+	n, err := m.WriteTo(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, "written", n, int64(b.Len()))
+
+	for _, line := range []string{
+		`directdecisions_requests_total{operation="Votings.Voting",status="429"} 1`,
+		`directdecisions_errors_total{operation="Votings.Voting",error="ErrHTTPStatusTooManyRequests"} 1`,
+		`directdecisions_request_duration_seconds_count{operation="Votings.Voting"} 1`,
+		`directdecisions_rate_limit_remaining 9`,
+	} {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Errorf("missing line %q", line)
+		}
+	}
+}
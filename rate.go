@@ -31,12 +31,16 @@ func (r Rate) String() (s string) {
 	return fmt.Sprintf("limit: %v, remaining %v, reset at %s", r.Limit, r.Remaining, r.Reset)
 }
 
-func (c *Client) setRate(r *http.Response) {
-	rate := parseRate(r)
+// setRate sets the rate limit information from the response as the current
+// rate of the Client and returns it.
+func (c *Client) setRate(r *http.Response) (rate Rate) {
+	rate = parseRate(r)
 
 	c.rateMu.Lock()
 	c.rate = rate
 	c.rateMu.Unlock()
+
+	return rate
 }
 
 // Rate returns the current request rate limit information.
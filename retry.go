@@ -0,0 +1,154 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryMinBackoff  = 500 * time.Millisecond
+	defaultRetryMaxBackoff  = 30 * time.Second
+)
+
+// RetryPolicy configures automatic retries of failed API requests.
+//
+// Requests are retried only if the response status is listed in Statuses and
+// the request is safe to repeat. Requests with idempotent HTTP methods (GET,
+// HEAD, OPTIONS, PUT and DELETE) are retried for every listed status, while
+// other requests are retried only on the Too Many Requests status, as the API
+// rejects them without processing, unless they are sent with an idempotency
+// key.
+//
+// On Too Many Requests, the Client waits until the time from the Retry-After
+// response header, exposed as Rate.Retry, if it is provided. Otherwise, it
+// waits for an exponentially increasing duration with a random jitter,
+// starting from MinBackoff and capped at MaxBackoff. Waiting is interrupted
+// when the request context is done.
+type RetryPolicy struct {
+	// MaxAttempts is the maximal number of attempts for a single request,
+	// including the first one. If it is zero, 3 attempts are made.
+	MaxAttempts int
+	// MinBackoff is the duration to wait before the first retry. If it is
+	// zero, 500 milliseconds is used.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximal duration between two attempts computed by
+	// the exponential backoff. If it is zero, 30 seconds is used.
+	MaxBackoff time.Duration
+	// Statuses are HTTP response status codes that should be retried. If it
+	// is nil, Too Many Requests, Bad Gateway and Service Unavailable
+	// statuses are retried.
+	Statuses []int
+}
+
+var defaultRetryStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+}
+
+// retrier decides if and when a failed request should be repeated based on the
+// RetryPolicy.
+type retrier struct {
+	maxAttempts int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	statuses    map[int]struct{}
+}
+
+func newRetrier(p *RetryPolicy) *retrier {
+	if p == nil {
+		return nil
+	}
+	r := &retrier{
+		maxAttempts: p.MaxAttempts,
+		minBackoff:  p.MinBackoff,
+		maxBackoff:  p.MaxBackoff,
+		statuses:    make(map[int]struct{}),
+	}
+	if r.maxAttempts <= 0 {
+		r.maxAttempts = defaultRetryMaxAttempts
+	}
+	if r.minBackoff <= 0 {
+		r.minBackoff = defaultRetryMinBackoff
+	}
+	if r.maxBackoff <= 0 {
+		r.maxBackoff = defaultRetryMaxBackoff
+	}
+	if r.maxBackoff < r.minBackoff {
+		r.maxBackoff = r.minBackoff
+	}
+	statuses := p.Statuses
+	if statuses == nil {
+		statuses = defaultRetryStatuses
+	}
+	for _, s := range statuses {
+		r.statuses[s] = struct{}{}
+	}
+	return r
+}
+
+// delay returns the duration to wait before the next attempt and true if the
+// request that received the response with the status code on the provided
+// attempt should be retried. Requests that are not idempotent are retried
+// only on Too Many Requests status.
+func (r *retrier) delay(idempotent bool, status, attempt int, rate Rate) (d time.Duration, ok bool) {
+	if r == nil || attempt >= r.maxAttempts {
+		return 0, false
+	}
+	if _, ok := r.statuses[status]; !ok {
+		return 0, false
+	}
+	if status != http.StatusTooManyRequests && !idempotent {
+		return 0, false
+	}
+	if status == http.StatusTooManyRequests && !rate.Retry.IsZero() {
+		if d := time.Until(rate.Retry); d > 0 {
+			return d, true
+		}
+	}
+	return r.backoff(attempt), true
+}
+
+// backoff returns an exponentially increasing duration for the attempt with
+// the lower half of the interval chosen at random.
+func (r *retrier) backoff(attempt int) time.Duration {
+	d := r.minBackoff
+	for i := 1; i < attempt && d < r.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > r.maxBackoff {
+		d = r.maxBackoff
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// sleep pauses the current goroutine for the duration d or until the context
+// is done, in which case the context error is returned.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
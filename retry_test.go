@@ -0,0 +1,174 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"directdecisions.com/directdecisions"
+)
+
+func TestRetry(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		method       string
+		status       int
+		policy       *directdecisions.RetryPolicy
+		wantAttempts int32
+		wantErr      error
+	}{
+		{
+			name:         "no policy",
+			method:       http.MethodGet,
+			status:       http.StatusServiceUnavailable,
+			wantAttempts: 1,
+			wantErr:      directdecisions.ErrHTTPStatusServiceUnavailable,
+		},
+		{
+			name:         "service unavailable",
+			method:       http.MethodGet,
+			status:       http.StatusServiceUnavailable,
+			policy:       &directdecisions.RetryPolicy{MaxAttempts: 4, MinBackoff: time.Millisecond},
+			wantAttempts: 4,
+			wantErr:      directdecisions.ErrHTTPStatusServiceUnavailable,
+		},
+		{
+			name:         "bad gateway",
+			method:       http.MethodDelete,
+			status:       http.StatusBadGateway,
+			policy:       &directdecisions.RetryPolicy{MinBackoff: time.Millisecond},
+			wantAttempts: 3,
+			wantErr:      directdecisions.ErrHTTPStatusBadGateway,
+		},
+		{
+			name:         "not idempotent",
+			method:       http.MethodPost,
+			status:       http.StatusServiceUnavailable,
+			policy:       &directdecisions.RetryPolicy{MinBackoff: time.Millisecond},
+			wantAttempts: 1,
+			wantErr:      directdecisions.ErrHTTPStatusServiceUnavailable,
+		},
+		{
+			name:         "too many requests not idempotent",
+			method:       http.MethodPost,
+			status:       http.StatusTooManyRequests,
+			policy:       &directdecisions.RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond},
+			wantAttempts: 2,
+			wantErr:      directdecisions.ErrHTTPStatusTooManyRequests,
+		},
+		{
+			name:         "status not listed",
+			method:       http.MethodGet,
+			status:       http.StatusServiceUnavailable,
+			policy:       &directdecisions.RetryPolicy{MinBackoff: time.Millisecond, Statuses: []int{http.StatusInternalServerError}},
+			wantAttempts: 1,
+			wantErr:      directdecisions.ErrHTTPStatusServiceUnavailable,
+		},
+		{
+			name:         "custom status",
+			method:       http.MethodGet,
+			status:       http.StatusInternalServerError,
+			policy:       &directdecisions.RetryPolicy{MinBackoff: time.Millisecond, Statuses: []int{http.StatusInternalServerError}},
+			wantAttempts: 3,
+			wantErr:      directdecisions.ErrHTTPStatusInternalServerError,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+				Retry: tc.policy,
+			})
+
+			var attempts int32
+			mux.HandleFunc("/v1/votings/40f80454800b2bd7c172/", requireMethod(tc.method, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				w.WriteHeader(tc.status)
+			}))
+
+			var err error
+			switch tc.method {
+			case http.MethodGet:
+				_, err = client.Votings.Ballot(context.Background(), "40f80454800b2bd7c172", "leonardo")
+			case http.MethodDelete:
+				err = client.Votings.Unvote(context.Background(), "40f80454800b2bd7c172", "leonardo")
+			case http.MethodPost:
+				_, err = client.Votings.Set(context.Background(), "40f80454800b2bd7c172", "Diavola", 1)
+			}
+			assertErrors(t, err, tc.wantErr)
+
+			assertEqual(t, "attempts", atomic.LoadInt32(&attempts), tc.wantAttempts)
+		})
+	}
+}
+
+func TestRetry_success(t *testing.T) {
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Retry: &directdecisions.RetryPolicy{MinBackoff: time.Millisecond},
+	})
+
+	var attempts int32
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		newStaticHandler(`{"id": "40f80454800b2bd7c172", "choices": ["Margarita", "Diavola", "Capricciosa"]}`)(w, r)
+	}))
+
+	got, err := client.Votings.Voting(context.Background(), "40f80454800b2bd7c172")
+	assertErrors(t, err, nil)
+
+	assertEqual(t, "", got, votingsServiceVotingWant)
+	assertEqual(t, "attempts", atomic.LoadInt32(&attempts), int32(2))
+}
+
+func TestRetry_retryAfter(t *testing.T) {
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Retry: &directdecisions.RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+	})
+
+	var attempts int32
+	mux.HandleFunc("/v1/votings", requireMethod("POST", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		newStaticHandler(`{"id": "40f80454800b2bd7c172"}`)(w, r)
+	}))
+
+	start := time.Now()
+	_, err := client.Votings.Create(context.Background(), []string{"Margarita", "Diavola"})
+	assertErrors(t, err, nil)
+
+	if d := time.Since(start); d < 500*time.Millisecond {
+		t.Errorf("got retry after %s, want about one second", d)
+	}
+	assertEqual(t, "attempts", atomic.LoadInt32(&attempts), int32(2))
+}
+
+func TestRetry_contextCanceled(t *testing.T) {
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Retry: &directdecisions.RetryPolicy{MinBackoff: time.Hour},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	_, err := client.Votings.Voting(ctx, "40f80454800b2bd7c172")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+}
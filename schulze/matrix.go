@@ -0,0 +1,187 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schulze
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"directdecisions.com/directdecisions"
+)
+
+// DuelMatrix holds pairwise preferences of every two choices and the
+// strengths of the strongest paths between them, built from duels in the
+// form returned by VotingsService.Duels.
+type DuelMatrix struct {
+	choices     []string // by choice index
+	index       map[string]int
+	preferences []int
+	strengths   []int
+}
+
+// NewDuelMatrix returns a DuelMatrix built from duels of all pairs of
+// choices. Error directdecisions.ErrInvalidData is returned if choice names
+// and indexes are not consistent or if a choice index is missing.
+func NewDuelMatrix(duels []directdecisions.Duel) (*DuelMatrix, error) {
+	names := make(map[int]string)
+	for _, d := range duels {
+		for _, s := range []directdecisions.ChoiceStrength{d.Left, d.Right} {
+			if s.Index < 0 {
+				return nil, fmt.Errorf("%w: invalid index %v of choice %q", directdecisions.ErrInvalidData, s.Index, s.Choice)
+			}
+			if name, ok := names[s.Index]; ok && name != s.Choice {
+				return nil, fmt.Errorf("%w: choices %q and %q with the same index %v", directdecisions.ErrInvalidData, name, s.Choice, s.Index)
+			}
+			names[s.Index] = s.Choice
+		}
+	}
+
+	n := len(names)
+	m := &DuelMatrix{
+		choices:     make([]string, n),
+		index:       make(map[string]int, n),
+		preferences: make([]int, n*n),
+	}
+	for i := 0; i < n; i++ {
+		name, ok := names[i]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing choice with index %v", directdecisions.ErrInvalidData, i)
+		}
+		if _, ok := m.index[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate choice %q", directdecisions.ErrInvalidData, name)
+		}
+		m.choices[i] = name
+		m.index[name] = i
+	}
+	for _, d := range duels {
+		l, r := d.Left.Index, d.Right.Index
+		m.preferences[l*n+r] = d.Left.Strength
+		m.preferences[r*n+l] = d.Right.Strength
+	}
+	m.strengths = strongestPaths(n, m.preferences)
+	return m, nil
+}
+
+// Choices returns choice names ordered by their indexes.
+func (m *DuelMatrix) Choices() []string {
+	return append([]string(nil), m.choices...)
+}
+
+// Index returns the index of the choice.
+func (m *DuelMatrix) Index(choice string) (i int, ok bool) {
+	i, ok = m.index[choice]
+	return i, ok
+}
+
+// Preference returns the number of voters that prefer the choice with index i
+// over the choice with index j.
+func (m *DuelMatrix) Preference(i, j int) int {
+	return m.preferences[m.offset(i, j)]
+}
+
+// Strength returns the strength of the strongest path from the choice with
+// index i to the choice with index j, or zero if there is no such path.
+func (m *DuelMatrix) Strength(i, j int) int {
+	return m.strengths[m.offset(i, j)]
+}
+
+// Duel returns pairwise preferences of two choices referenced by their names.
+func (m *DuelMatrix) Duel(left, right string) (d directdecisions.Duel, ok bool) {
+	l, lok := m.index[left]
+	r, rok := m.index[right]
+	if !lok || !rok || l == r {
+		return d, false
+	}
+	return directdecisions.Duel{
+		Left:  directdecisions.ChoiceStrength{Choice: left, Index: l, Strength: m.Preference(l, r)},
+		Right: directdecisions.ChoiceStrength{Choice: right, Index: r, Strength: m.Preference(r, l)},
+	}, true
+}
+
+// CondorcetWinner returns the choice that more voters prefer over every other
+// choice, if there is one.
+func (m *DuelMatrix) CondorcetWinner() (choice string, ok bool) {
+	return m.condorcet(func(ij, ji int) bool { return ij > ji })
+}
+
+// CondorcetLoser returns the choice that more voters prefer every other
+// choice over, if there is one.
+func (m *DuelMatrix) CondorcetLoser() (choice string, ok bool) {
+	return m.condorcet(func(ij, ji int) bool { return ij < ji })
+}
+
+func (m *DuelMatrix) condorcet(beats func(ij, ji int) bool) (choice string, ok bool) {
+	n := len(m.choices)
+	if n < 2 {
+		return "", false
+	}
+	for i := 0; i < n; i++ {
+		all := true
+		for j := 0; j < n && all; j++ {
+			all = i == j || beats(m.Preference(i, j), m.Preference(j, i))
+		}
+		if all {
+			return m.choices[i], true
+		}
+	}
+	return "", false
+}
+
+// DOT writes the beat-path relation as a Graphviz DOT directed graph. There is
+// an edge from a choice to every choice that it beats with a stronger
+// strongest path, labeled with the path strength. Edges are dashed if the
+// choice does not also win the direct pairwise comparison. Choices that are
+// not beaten by any other choice are drawn with double borders.
+func (m *DuelMatrix) DOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	n := len(m.choices)
+
+	fmt.Fprintln(bw, "digraph duels {")
+	fmt.Fprintln(bw, "\tnode [shape=box];")
+	for i, c := range m.choices {
+		beaten := false
+		for j := 0; j < n && !beaten; j++ {
+			beaten = m.Strength(j, i) > m.Strength(i, j)
+		}
+		if beaten {
+			fmt.Fprintf(bw, "\t%s;\n", dotQuote(c))
+		} else {
+			fmt.Fprintf(bw, "\t%s [peripheries=2];\n", dotQuote(c))
+		}
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i == j || m.Strength(i, j) <= m.Strength(j, i) {
+				continue
+			}
+			style := ""
+			if m.Preference(i, j) <= m.Preference(j, i) {
+				style = ", style=dashed"
+			}
+			fmt.Fprintf(bw, "\t%s -> %s [label=\"%v\"%s];\n", dotQuote(m.choices[i]), dotQuote(m.choices[j]), m.Strength(i, j), style)
+		}
+	}
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
+
+func (m *DuelMatrix) offset(i, j int) int {
+	n := len(m.choices)
+	if i < 0 || i >= n || j < 0 || j >= n {
+		panic(fmt.Sprintf("schulze: choice index out of range [%v, %v] with %v choices", i, j, n))
+	}
+	return i*n + j
+}
+
+var dotReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// dotQuote returns the DOT quoted string.
+func dotQuote(s string) string {
+	return `"` + dotReplacer.Replace(s) + `"`
+}
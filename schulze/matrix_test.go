@@ -0,0 +1,184 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schulze_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"directdecisions.com/directdecisions"
+	"directdecisions.com/directdecisions/schulze"
+)
+
+func TestDuelMatrix(t *testing.T) {
+	m := newDuelMatrix(t, []string{"Margarita", "Pepperoni", "Capricciosa"}, []map[string]int{
+		{"Pepperoni": 1, "Margarita": 2, "Capricciosa": 3},
+		{"Pepperoni": 1, "Capricciosa": 2, "Margarita": 3},
+		{"Margarita": 1, "Pepperoni": 2, "Capricciosa": 3},
+	})
+
+	assertEqual(t, "choices", m.Choices(), []string{"Margarita", "Pepperoni", "Capricciosa"})
+
+	i, ok := m.Index("Pepperoni")
+	assertEqual(t, "index", i, 1)
+	assertEqual(t, "index ok", ok, true)
+	_, ok = m.Index("Hawaiian")
+	assertEqual(t, "unknown index ok", ok, false)
+
+	assertEqual(t, "preference", m.Preference(1, 0), 2)
+	assertEqual(t, "preference", m.Preference(0, 1), 1)
+	assertEqual(t, "strength", m.Strength(1, 2), 3)
+	assertEqual(t, "strength", m.Strength(2, 1), 0)
+
+	d, ok := m.Duel("Capricciosa", "Margarita")
+	assertEqual(t, "duel ok", ok, true)
+	assertEqual(t, "duel", d, directdecisions.Duel{
+		Left:  directdecisions.ChoiceStrength{Choice: "Capricciosa", Index: 2, Strength: 1},
+		Right: directdecisions.ChoiceStrength{Choice: "Margarita", Index: 0, Strength: 2},
+	})
+	_, ok = m.Duel("Margarita", "Margarita")
+	assertEqual(t, "same duel ok", ok, false)
+
+	winner, ok := m.CondorcetWinner()
+	assertEqual(t, "winner", winner, "Pepperoni")
+	assertEqual(t, "winner ok", ok, true)
+
+	loser, ok := m.CondorcetLoser()
+	assertEqual(t, "loser", loser, "Capricciosa")
+	assertEqual(t, "loser ok", ok, true)
+
+	var buf bytes.Buffer
+	if err := m.DOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, "dot", buf.String(), `digraph duels {
+	node [shape=box];
+	"Margarita";
+	"Pepperoni" [peripheries=2];
+	"Capricciosa";
+	"Margarita" -> "Capricciosa" [label="2"];
+	"Pepperoni" -> "Margarita" [label="2"];
+	"Pepperoni" -> "Capricciosa" [label="3"];
+}
+`)
+}
+
+func TestDuelMatrix_strongestPaths(t *testing.T) {
+	// Example from https://en.wikipedia.org/wiki/Schulze_method.
+	m := newDuelMatrix(t, []string{"A", "B", "C", "D", "E"}, wikipediaBallots())
+
+	assertEqual(t, "strengths", strengths(m), [][]int{
+		{0, 28, 28, 30, 24},
+		{25, 0, 28, 33, 24},
+		{25, 29, 0, 29, 24},
+		{25, 28, 28, 0, 24},
+		{25, 28, 28, 31, 0},
+	})
+
+	_, ok := m.CondorcetWinner()
+	assertEqual(t, "winner ok", ok, false)
+	_, ok = m.CondorcetLoser()
+	assertEqual(t, "loser ok", ok, false)
+
+	var buf bytes.Buffer
+	if err := m.DOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, "dot", buf.String(), `digraph duels {
+	node [shape=box];
+	"A";
+	"B";
+	"C";
+	"D";
+	"E" [peripheries=2];
+	"A" -> "B" [label="28", style=dashed];
+	"A" -> "C" [label="28"];
+	"A" -> "D" [label="30"];
+	"B" -> "D" [label="33"];
+	"C" -> "B" [label="29"];
+	"C" -> "D" [label="29", style=dashed];
+	"E" -> "A" [label="25"];
+	"E" -> "B" [label="28"];
+	"E" -> "C" [label="28", style=dashed];
+	"E" -> "D" [label="31"];
+}
+`)
+}
+
+func TestNewDuelMatrix_invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		duels []directdecisions.Duel
+	}{
+		{
+			name: "missing index",
+			duels: []directdecisions.Duel{
+				{
+					Left:  directdecisions.ChoiceStrength{Choice: "Margarita", Index: 0},
+					Right: directdecisions.ChoiceStrength{Choice: "Pepperoni", Index: 2},
+				},
+			},
+		},
+		{
+			name: "inconsistent names",
+			duels: []directdecisions.Duel{
+				{
+					Left:  directdecisions.ChoiceStrength{Choice: "Margarita", Index: 0},
+					Right: directdecisions.ChoiceStrength{Choice: "Pepperoni", Index: 1},
+				},
+				{
+					Left:  directdecisions.ChoiceStrength{Choice: "Capricciosa", Index: 0},
+					Right: directdecisions.ChoiceStrength{Choice: "Pepperoni", Index: 1},
+				},
+			},
+		},
+		{
+			name: "duplicate choice",
+			duels: []directdecisions.Duel{
+				{
+					Left:  directdecisions.ChoiceStrength{Choice: "Margarita", Index: 0},
+					Right: directdecisions.ChoiceStrength{Choice: "Margarita", Index: 1},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := schulze.NewDuelMatrix(tc.duels)
+			if !errors.Is(err, directdecisions.ErrInvalidData) {
+				t.Errorf("got error %v, want %v", err, directdecisions.ErrInvalidData)
+			}
+		})
+	}
+}
+
+func newDuelMatrix(t testing.TB, choices []string, ballots []map[string]int) *schulze.DuelMatrix {
+	t.Helper()
+
+	_, duels, _, err := schulze.Duels(choices, ballots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := schulze.NewDuelMatrix(duels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func strengths(m *schulze.DuelMatrix) [][]int {
+	n := len(m.Choices())
+	s := make([][]int, n)
+	for i := range s {
+		s[i] = make([]int, n)
+		for j := range s[i] {
+			if i != j {
+				s[i][j] = m.Strength(i, j)
+			}
+		}
+	}
+	return s
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package schulze computes results of Direct Decisions votings locally with the
+// Schulze method, in the same form as they are returned by the
+// directdecisions VotingsService.
+package schulze
+
+import (
+	"fmt"
+	"sort"
+
+	"directdecisions.com/directdecisions"
+)
+
+// Results returns ranked choices and the tie flag for the choices and ballots
+// in the same form as VotingsService.Results. Ballots have the same form as
+// in VotingsService.Vote, where choices with lower ranks are more preferred
+// and choices that are not ranked are less preferred than all ranked ones.
+//
+// Error directdecisions.ErrInvalidData is returned if choices are not unique or
+// if a ballot contains an unknown choice or a rank that is not positive.
+func Results(choices []string, ballots []map[string]int) (results []directdecisions.Result, tie bool, err error) {
+	results, _, tie, err = Duels(choices, ballots)
+	return results, tie, err
+}
+
+// Duels returns ranked choices, pairwise preference counts for every two
+// choices and the tie flag in the same form as VotingsService.Duels. Ballots
+// and errors are the same as for the Results function.
+func Duels(choices []string, ballots []map[string]int) (results []directdecisions.Result, duels []directdecisions.Duel, tie bool, err error) {
+	if err := Validate(choices, ballots); err != nil {
+		return nil, nil, false, err
+	}
+	preferences := PairwisePreferences(choices, ballots)
+	strengths := strongestPaths(len(choices), preferences)
+	results, tie = rank(choices, strengths)
+	return results, pairwiseDuels(choices, preferences), tie, nil
+}
+
+// Validate returns directdecisions.ErrInvalidData if choices are not unique or
+// if a ballot contains an unknown choice or a rank that is not positive.
+func Validate(choices []string, ballots []map[string]int) error {
+	index := make(map[string]struct{}, len(choices))
+	for _, c := range choices {
+		if _, ok := index[c]; ok {
+			return fmt.Errorf("%w: duplicate choice %q", directdecisions.ErrInvalidData, c)
+		}
+		index[c] = struct{}{}
+	}
+	for i, b := range ballots {
+		for c, r := range b {
+			if _, ok := index[c]; !ok {
+				return fmt.Errorf("%w: ballot %v: unknown choice %q", directdecisions.ErrInvalidData, i, c)
+			}
+			if r <= 0 {
+				return fmt.Errorf("%w: ballot %v: invalid rank %v for choice %q", directdecisions.ErrInvalidData, i, r, c)
+			}
+		}
+	}
+	return nil
+}
+
+// PairwisePreferences returns the number of ballots that prefer choice i over
+// choice j at the index i*len(choices)+j. Choices that are not ranked on a
+// ballot are less preferred than the ranked ones and equal to each other.
+// Ballots are expected to be valid, as reported by Validate.
+func PairwisePreferences(choices []string, ballots []map[string]int) []int {
+	n := len(choices)
+	preferences := make([]int, n*n)
+	ranks := make([]int, n)
+
+	for _, b := range ballots {
+		for i, c := range choices {
+			ranks[i] = b[c] // zero if unranked
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if i == j || ranks[i] == 0 {
+					continue
+				}
+				if ranks[j] == 0 || ranks[i] < ranks[j] {
+					preferences[i*n+j]++
+				}
+			}
+		}
+	}
+	return preferences
+}
+
+// strongestPaths computes the strengths of the strongest paths between every
+// two choices with the Floyd–Warshall algorithm.
+func strongestPaths(n int, preferences []int) []int {
+	strengths := make([]int, n*n)
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i == j {
+				continue
+			}
+			if ij, ji := preferences[i*n+j], preferences[j*n+i]; ij > ji {
+				strengths[i*n+j] = ij
+			}
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i == j || strengths[j*n+i] == 0 {
+				continue
+			}
+			for k := 0; k < n; k++ {
+				if i == k || j == k {
+					continue
+				}
+				s := strengths[j*n+i]
+				if ik := strengths[i*n+k]; ik < s {
+					s = ik
+				}
+				if s > strengths[j*n+k] {
+					strengths[j*n+k] = s
+				}
+			}
+		}
+	}
+	return strengths
+}
+
+// rank orders choices by the number of wins in the strongest path comparisons,
+// then by the sum of their strongest path strengths and the sum of strength
+// differences to the choices that they win against.
+func rank(choices []string, strengths []int) (results []directdecisions.Result, tie bool) {
+	n := len(choices)
+	results = make([]directdecisions.Result, 0, n)
+
+	for i, c := range choices {
+		var wins, strength, advantage int
+		for j := 0; j < n; j++ {
+			if i == j {
+				continue
+			}
+			ij, ji := strengths[i*n+j], strengths[j*n+i]
+			if ij > ji {
+				wins++
+				advantage += ij - ji
+			}
+			strength += ij
+		}
+		var percentage float64
+		if n > 1 {
+			percentage = float64(wins) / float64(n-1) * 100
+		}
+		results = append(results, directdecisions.Result{
+			Choice:     c,
+			Index:      i,
+			Wins:       wins,
+			Percentage: percentage,
+			Strength:   strength,
+			Advantage:  advantage,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Wins != results[j].Wins {
+			return results[i].Wins > results[j].Wins
+		}
+		if results[i].Strength != results[j].Strength {
+			return results[i].Strength > results[j].Strength
+		}
+		return results[i].Advantage > results[j].Advantage
+	})
+
+	if len(results) > 1 {
+		tie = results[0].Wins == results[1].Wins
+	}
+	return results, tie
+}
+
+// pairwiseDuels returns preference counts for every two choices.
+func pairwiseDuels(choices []string, preferences []int) []directdecisions.Duel {
+	n := len(choices)
+	duels := make([]directdecisions.Duel, 0, n*(n-1)/2)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			duels = append(duels, directdecisions.Duel{
+				Left: directdecisions.ChoiceStrength{
+					Choice:   choices[i],
+					Index:    i,
+					Strength: preferences[i*n+j],
+				},
+				Right: directdecisions.ChoiceStrength{
+					Choice:   choices[j],
+					Index:    j,
+					Strength: preferences[j*n+i],
+				},
+			})
+		}
+	}
+	return duels
+}
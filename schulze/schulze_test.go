@@ -0,0 +1,151 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schulze_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"directdecisions.com/directdecisions"
+	"directdecisions.com/directdecisions/schulze"
+)
+
+func TestResults(t *testing.T) {
+	// Example from https://en.wikipedia.org/wiki/Schulze_method.
+	choices := []string{"A", "B", "C", "D", "E"}
+
+	results, tie, err := schulze.Results(choices, wikipediaBallots())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, "tie", tie, false)
+	assertEqual(t, "results", results, []directdecisions.Result{
+		{Choice: "E", Index: 4, Wins: 4, Percentage: 100, Strength: 112, Advantage: 16},
+		{Choice: "A", Index: 0, Wins: 3, Percentage: 75, Strength: 110, Advantage: 11},
+		{Choice: "C", Index: 2, Wins: 2, Percentage: 50, Strength: 107, Advantage: 2},
+		{Choice: "B", Index: 1, Wins: 1, Percentage: 25, Strength: 110, Advantage: 5},
+		{Choice: "D", Index: 3, Wins: 0, Percentage: 0, Strength: 105, Advantage: 0},
+	})
+}
+
+func TestDuels(t *testing.T) {
+	choices := []string{"Margarita", "Pepperoni", "Capricciosa"}
+
+	results, duels, tie, err := schulze.Duels(choices, []map[string]int{
+		{"Pepperoni": 1, "Margarita": 2},
+		{"Capricciosa": 1, "Margarita": 2, "Pepperoni": 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, "tie", tie, false)
+	assertEqual(t, "results", results, []directdecisions.Result{
+		{Choice: "Pepperoni", Index: 1, Wins: 1, Percentage: 50, Strength: 1, Advantage: 1},
+		{Choice: "Margarita", Index: 0, Wins: 0, Percentage: 0, Strength: 0, Advantage: 0},
+		{Choice: "Capricciosa", Index: 2, Wins: 0, Percentage: 0, Strength: 0, Advantage: 0},
+	})
+	assertEqual(t, "duels", duels, []directdecisions.Duel{
+		{
+			Left:  directdecisions.ChoiceStrength{Choice: "Margarita", Index: 0, Strength: 0},
+			Right: directdecisions.ChoiceStrength{Choice: "Pepperoni", Index: 1, Strength: 1},
+		},
+		{
+			Left:  directdecisions.ChoiceStrength{Choice: "Margarita", Index: 0, Strength: 1},
+			Right: directdecisions.ChoiceStrength{Choice: "Capricciosa", Index: 2, Strength: 1},
+		},
+		{
+			Left:  directdecisions.ChoiceStrength{Choice: "Pepperoni", Index: 1, Strength: 1},
+			Right: directdecisions.ChoiceStrength{Choice: "Capricciosa", Index: 2, Strength: 1},
+		},
+	})
+}
+
+func TestResults_noBallots(t *testing.T) {
+	results, tie, err := schulze.Results([]string{"Margarita", "Pepperoni"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, "tie", tie, true)
+	assertEqual(t, "results", results, []directdecisions.Result{
+		{Choice: "Margarita", Index: 0},
+		{Choice: "Pepperoni", Index: 1},
+	})
+}
+
+func TestResults_invalidData(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		choices []string
+		ballots []map[string]int
+	}{
+		{
+			name:    "duplicate choice",
+			choices: []string{"Margarita", "Margarita"},
+		},
+		{
+			name:    "unknown choice",
+			choices: []string{"Margarita", "Pepperoni"},
+			ballots: []map[string]int{{"Diavola": 1}},
+		},
+		{
+			name:    "zero rank",
+			choices: []string{"Margarita", "Pepperoni"},
+			ballots: []map[string]int{{"Margarita": 0}},
+		},
+		{
+			name:    "negative rank",
+			choices: []string{"Margarita", "Pepperoni"},
+			ballots: []map[string]int{{"Margarita": -1}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := schulze.Results(tc.choices, tc.ballots)
+			if !errors.Is(err, directdecisions.ErrInvalidData) {
+				t.Errorf("got error %v, want %v", err, directdecisions.ErrInvalidData)
+			}
+		})
+	}
+}
+
+func assertEqual(t testing.TB, name string, got, want any) {
+	t.Helper()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s: got %+v, want %+v", name, got, want)
+	}
+}
+
+// wikipediaBallots returns ballots of the example from
+// https://en.wikipedia.org/wiki/Schulze_method.
+func wikipediaBallots() []map[string]int {
+	var ballots []map[string]int
+	for _, g := range []struct {
+		count int
+		order string
+	}{
+		{5, "ACBED"},
+		{5, "ADECB"},
+		{8, "BEDAC"},
+		{3, "CABED"},
+		{7, "CAEBD"},
+		{2, "CBADE"},
+		{7, "DCEBA"},
+		{8, "EBADC"},
+	} {
+		for i := 0; i < g.count; i++ {
+			ballot := make(map[string]int)
+			for rank, c := range g.order {
+				ballot[string(c)] = rank + 1
+			}
+			ballots = append(ballots, ballot)
+		}
+	}
+	return ballots
+}
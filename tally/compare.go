@@ -0,0 +1,151 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tally
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"directdecisions.com/directdecisions"
+	"directdecisions.com/directdecisions/schulze"
+)
+
+// Comparison holds rankings of choices by different methods compared to the
+// Schulze ranking.
+type Comparison struct {
+	Schulze []Standing
+	Methods []MethodComparison
+}
+
+// MethodComparison holds the ranking by a method and its differences from
+// the Schulze ranking.
+type MethodComparison struct {
+	Method      Method
+	Standings   []Standing
+	SameWinners bool         // Both rankings have the same choices at the first place.
+	Differences []Difference // Choices that are placed differently, in the Schulze order.
+}
+
+// Difference is a choice that is placed differently than by the Schulze
+// method.
+type Difference struct {
+	Choice  string
+	Schulze int // Place by the Schulze method.
+	Place   int // Place by the compared method.
+}
+
+// Compare ranks choices with all Methods and compares them with the Schulze
+// results, such as the ones returned by VotingsService.Results. If results
+// are nil, they are computed locally. Choices are placed by results with the
+// same number of wins at the same place. Errors are the same as for Rank.
+func Compare(choices []string, ballots []map[string]int, results []directdecisions.Result) (*Comparison, error) {
+	var reference []Standing
+	if results == nil {
+		s, err := Rank(Schulze, choices, ballots)
+		if err != nil {
+			return nil, err
+		}
+		reference = s
+	} else {
+		if err := schulze.Validate(choices, ballots); err != nil {
+			return nil, err
+		}
+		scores := make([]float64, len(choices))
+		for _, r := range results {
+			if r.Index < 0 || r.Index >= len(choices) || choices[r.Index] != r.Choice {
+				return nil, fmt.Errorf("%w: result choice %q with index %v", directdecisions.ErrInvalidData, r.Choice, r.Index)
+			}
+			scores[r.Index] = float64(r.Wins)
+		}
+		reference = standings(choices, scores, true)
+	}
+
+	places := make([]int, len(choices)) // Schulze places by choice index
+	for _, s := range reference {
+		places[s.Index] = s.Place
+	}
+
+	c := &Comparison{Schulze: reference}
+	for _, m := range Methods {
+		s, err := Rank(m, choices, ballots)
+		if err != nil {
+			return nil, err
+		}
+		mc := MethodComparison{
+			Method:      m,
+			Standings:   s,
+			SameWinners: true,
+		}
+		other := make([]int, len(choices))
+		for _, e := range s {
+			other[e.Index] = e.Place
+		}
+		for _, e := range reference {
+			if (e.Place == 1) != (other[e.Index] == 1) {
+				mc.SameWinners = false
+			}
+			if e.Place != other[e.Index] {
+				mc.Differences = append(mc.Differences, Difference{
+					Choice:  e.Choice,
+					Schulze: e.Place,
+					Place:   other[e.Index],
+				})
+			}
+		}
+		c.Methods = append(c.Methods, mc)
+	}
+	return c, nil
+}
+
+// WriteTo writes a table with places of choices by every method, in the
+// Schulze order. Places that differ from the Schulze place are marked with an
+// asterisk.
+func (c *Comparison) WriteTo(w io.Writer) (n int64, err error) {
+	cw := &countWriter{w: w}
+	tw := tabwriter.NewWriter(cw, 0, 4, 2, ' ', 0)
+
+	fmt.Fprint(tw, "CHOICE\t"+string(Schulze))
+	for _, m := range c.Methods {
+		fmt.Fprint(tw, "\t"+string(m.Method))
+	}
+	fmt.Fprintln(tw)
+
+	for _, s := range c.Schulze {
+		fmt.Fprintf(tw, "%s\t%v", s.Choice, s.Place)
+		for _, m := range c.Methods {
+			place := 0
+			for _, e := range m.Standings {
+				if e.Index == s.Index {
+					place = e.Place
+					break
+				}
+			}
+			p := strconv.Itoa(place)
+			if place != s.Place {
+				p += "*"
+			}
+			fmt.Fprint(tw, "\t"+p)
+		}
+		fmt.Fprintln(tw)
+	}
+
+	err = tw.Flush()
+	return cw.n, err
+}
+
+// countWriter counts the number of bytes written to the underlying writer.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (n int, err error) {
+	n, err = w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
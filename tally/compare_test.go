@@ -0,0 +1,104 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tally_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"directdecisions.com/directdecisions"
+	"directdecisions.com/directdecisions/schulze"
+	"directdecisions.com/directdecisions/tally"
+)
+
+func TestCompare(t *testing.T) {
+	results, _, err := schulze.Results(tennesseeChoices, tennesseeBallots())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := tally.Compare(tennesseeChoices, tennesseeBallots(), results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type summary struct {
+		method      tally.Method
+		sameWinners bool
+		differences []tally.Difference
+	}
+	var got []summary
+	for _, m := range c.Methods {
+		got = append(got, summary{m.Method, m.SameWinners, m.Differences})
+	}
+	assertEqual(t, "comparison", got, []summary{
+		{
+			method: tally.InstantRunoff,
+			differences: []tally.Difference{
+				{Choice: "Nashville", Schulze: 1, Place: 3},
+				{Choice: "Chattanooga", Schulze: 2, Place: 4},
+				{Choice: "Knoxville", Schulze: 3, Place: 1},
+				{Choice: "Memphis", Schulze: 4, Place: 2},
+			},
+		},
+		{
+			method:      tally.Borda,
+			sameWinners: true,
+			differences: []tally.Difference{
+				{Choice: "Knoxville", Schulze: 3, Place: 4},
+				{Choice: "Memphis", Schulze: 4, Place: 3},
+			},
+		},
+		{method: tally.Copeland, sameWinners: true},
+		{method: tally.RankedPairs, sameWinners: true},
+		{
+			method:      tally.Minimax,
+			sameWinners: true,
+			differences: []tally.Difference{
+				{Choice: "Chattanooga", Schulze: 2, Place: 3},
+				{Choice: "Knoxville", Schulze: 3, Place: 4},
+				{Choice: "Memphis", Schulze: 4, Place: 2},
+			},
+		},
+		{
+			method: tally.Plurality,
+			differences: []tally.Difference{
+				{Choice: "Nashville", Schulze: 1, Place: 2},
+				{Choice: "Chattanooga", Schulze: 2, Place: 4},
+				{Choice: "Memphis", Schulze: 4, Place: 1},
+			},
+		},
+	})
+
+	var b strings.Builder
+	if _, err := c.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, "table", b.String(), `CHOICE       schulze  instant-runoff  borda  copeland  ranked-pairs  minimax  plurality
+Nashville    1        3*              1      1         1             1        2*
+Chattanooga  2        4*              2      2         2             3*       4*
+Knoxville    3        1*              4*     3         3             4*       3
+Memphis      4        2*              3*     4         4             2*       1*
+`)
+}
+
+func TestCompare_localSchulze(t *testing.T) {
+	c, err := tally.Compare(tennesseeChoices, tennesseeBallots(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, "schulze winner", c.Schulze[0].Choice, "Nashville")
+}
+
+func TestCompare_invalidResults(t *testing.T) {
+	_, err := tally.Compare(tennesseeChoices, tennesseeBallots(), []directdecisions.Result{
+		{Choice: "Memphis", Index: 2},
+	})
+	if !errors.Is(err, directdecisions.ErrInvalidData) {
+		t.Errorf("got error %v, want %v", err, directdecisions.ErrInvalidData)
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tally
+
+import (
+	"sort"
+
+	"directdecisions.com/directdecisions/schulze"
+)
+
+func schulzeScores(choices []string, ballots []map[string]int) []float64 {
+	results, _, _ := schulze.Results(choices, ballots)
+	scores := make([]float64, len(choices))
+	for _, r := range results {
+		scores[r.Index] = float64(r.Wins)
+	}
+	return scores
+}
+
+// instantRunoff counts ballots for their most preferred continuing choices,
+// splitting ballots equally between tied ones, and eliminates choices with
+// the fewest votes round by round. Choices are placed in the reverse order of
+// elimination and choices eliminated in the same round share the place.
+func instantRunoff(choices []string, ballots []map[string]int) []Standing {
+	n := len(choices)
+	continuing := make(map[string]bool, n)
+	for _, c := range choices {
+		continuing[c] = true
+	}
+	scores := make([]float64, n)
+	var rounds [][]int // eliminated choice indexes by round
+
+	for remaining := n; remaining > 0; {
+		votes := make(map[string]float64, remaining)
+		for _, b := range ballots {
+			top := 0
+			for c, r := range b {
+				if continuing[c] && (top == 0 || r < top) {
+					top = r
+				}
+			}
+			if top == 0 {
+				// Ballots that rank none of the continuing choices are exhausted.
+				continue
+			}
+			var tied []string
+			for c, r := range b {
+				if continuing[c] && r == top {
+					tied = append(tied, c)
+				}
+			}
+			for _, c := range tied {
+				votes[c] += 1 / float64(len(tied))
+			}
+		}
+
+		fewest := -1.0
+		for _, c := range choices {
+			if continuing[c] && (fewest < 0 || votes[c] < fewest) {
+				fewest = votes[c]
+			}
+		}
+		var eliminated []int
+		for i, c := range choices {
+			if continuing[c] && votes[c] == fewest {
+				eliminated = append(eliminated, i)
+			}
+		}
+		for _, i := range eliminated {
+			continuing[choices[i]] = false
+			scores[i] = fewest
+		}
+		remaining -= len(eliminated)
+		rounds = append(rounds, eliminated)
+
+		if remaining == 1 {
+			// The winner keeps votes from the last round with opponents.
+			for i, c := range choices {
+				if continuing[c] {
+					scores[i] = votes[c]
+					rounds = append(rounds, []int{i})
+				}
+			}
+			break
+		}
+	}
+
+	s := make([]Standing, 0, n)
+	place := 1
+	for r := len(rounds) - 1; r >= 0; r-- {
+		for _, i := range rounds[r] {
+			s = append(s, Standing{Choice: choices[i], Index: i, Place: place, Score: scores[i]})
+		}
+		place += len(rounds[r])
+	}
+	return s
+}
+
+func bordaScores(choices []string, ballots []map[string]int) []float64 {
+	n := len(choices)
+	scores := make([]float64, n)
+	for _, b := range ballots {
+		r := ranks(choices, b)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				switch {
+				case i == j:
+				case r[i] < r[j]:
+					scores[i]++
+				case r[i] == r[j]:
+					scores[i] += 0.5
+				}
+			}
+		}
+	}
+	return scores
+}
+
+func copelandScores(n int, preferences []int) []float64 {
+	scores := make([]float64, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i == j {
+				continue
+			}
+			switch ij, ji := preferences[i*n+j], preferences[j*n+i]; {
+			case ij > ji:
+				scores[i]++
+			case ij == ji:
+				scores[i] += 0.5
+			}
+		}
+	}
+	return scores
+}
+
+// rankedPairsScores locks pairwise wins from the strongest one, skipping
+// those that would create a cycle, and scores choices by the number of
+// choices that they reach through locked pairs. Pairs are ordered by the
+// number of voters that prefer the winner, then by the smaller number of
+// voters that prefer the loser, and then by choice indexes.
+func rankedPairsScores(n int, preferences []int) []float64 {
+	type pair struct{ winner, loser, votes, opposition int }
+	var pairs []pair
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if ij, ji := preferences[i*n+j], preferences[j*n+i]; ij > ji {
+				pairs = append(pairs, pair{i, j, ij, ji})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(a, b int) bool {
+		if pairs[a].votes != pairs[b].votes {
+			return pairs[a].votes > pairs[b].votes
+		}
+		return pairs[a].opposition < pairs[b].opposition
+	})
+
+	locked := make([]bool, n*n)
+	reaches := func(from, to int) bool {
+		seen := make([]bool, n)
+		stack := []int{from}
+		for len(stack) > 0 {
+			c := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if c == to {
+				return true
+			}
+			if seen[c] {
+				continue
+			}
+			seen[c] = true
+			for k := 0; k < n; k++ {
+				if locked[c*n+k] {
+					stack = append(stack, k)
+				}
+			}
+		}
+		return false
+	}
+	for _, p := range pairs {
+		if !reaches(p.loser, p.winner) {
+			locked[p.winner*n+p.loser] = true
+		}
+	}
+
+	scores := make([]float64, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j && reaches(i, j) {
+				scores[i]++
+			}
+		}
+	}
+	return scores
+}
+
+func minimaxScores(n int, preferences []int) []float64 {
+	scores := make([]float64, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if ji := float64(preferences[j*n+i]); i != j && ji > scores[i] {
+				scores[i] = ji
+			}
+		}
+	}
+	return scores
+}
+
+func pluralityScores(choices []string, ballots []map[string]int) []float64 {
+	scores := make([]float64, len(choices))
+	for _, b := range ballots {
+		if len(b) == 0 {
+			continue
+		}
+		top := 0
+		for _, r := range b {
+			if top == 0 || r < top {
+				top = r
+			}
+		}
+		var first []int
+		for i, c := range choices {
+			if b[c] == top {
+				first = append(first, i)
+			}
+		}
+		for _, i := range first {
+			scores[i] += 1 / float64(len(first))
+		}
+	}
+	return scores
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package tally ranks choices of Direct Decisions votings locally with voting
+// methods other than the Schulze method that the API uses, and compares their
+// rankings with the Schulze results.
+//
+// Ballots have the same form as in directdecisions VotingsService.Vote method,
+// where choices with lower ranks are more preferred, choices with equal ranks
+// are tied and choices that are not ranked are less preferred than all ranked
+// ones.
+package tally
+
+import (
+	"fmt"
+	"sort"
+
+	"directdecisions.com/directdecisions"
+	"directdecisions.com/directdecisions/schulze"
+)
+
+// Method is the name of a voting method.
+type Method string
+
+// Supported voting methods.
+const (
+	Schulze       Method = "schulze"
+	InstantRunoff Method = "instant-runoff"
+	Borda         Method = "borda"
+	Copeland      Method = "copeland"
+	RankedPairs   Method = "ranked-pairs"
+	Minimax       Method = "minimax"
+	Plurality     Method = "plurality"
+)
+
+// Methods are all supported voting methods except Schulze, in the order in
+// which they are compared.
+var Methods = []Method{InstantRunoff, Borda, Copeland, RankedPairs, Minimax, Plurality}
+
+// Standing is the position of a choice in a ranking.
+type Standing struct {
+	Choice string
+	Index  int     // Index of the choice in the voting.
+	Place  int     // Place starting from 1, the same for tied choices.
+	Score  float64 // Method specific score, see Rank.
+}
+
+// Rank ranks choices with the method. Standings are ordered by place and then
+// by choice index. Scores are:
+//
+//   - Schulze: number of wins in strongest path comparisons,
+//   - InstantRunoff: votes in the round in which the choice was eliminated,
+//     or in the last round for winners,
+//   - Borda: points for every choice ranked lower, and half a point for every
+//     tied choice, on every ballot,
+//   - Copeland: pairwise comparison wins, and half for every draw,
+//   - RankedPairs: number of choices that the choice beats through locked
+//     pairs,
+//   - Minimax: the largest number of voters that prefer some other choice,
+//     where lower is better,
+//   - Plurality: number of ballots that rank the choice first, split between
+//     choices tied at the first rank.
+//
+// Error directdecisions.ErrInvalidData is returned if choices are not unique,
+// if a ballot contains an unknown choice or a rank that is not positive, or
+// if the method is not supported.
+func Rank(m Method, choices []string, ballots []map[string]int) ([]Standing, error) {
+	if err := schulze.Validate(choices, ballots); err != nil {
+		return nil, err
+	}
+	var scores []float64
+	higherIsBetter := true
+	switch m {
+	case Schulze:
+		scores = schulzeScores(choices, ballots)
+	case InstantRunoff:
+		return instantRunoff(choices, ballots), nil
+	case Borda:
+		scores = bordaScores(choices, ballots)
+	case Copeland:
+		scores = copelandScores(len(choices), schulze.PairwisePreferences(choices, ballots))
+	case RankedPairs:
+		scores = rankedPairsScores(len(choices), schulze.PairwisePreferences(choices, ballots))
+	case Minimax:
+		scores = minimaxScores(len(choices), schulze.PairwisePreferences(choices, ballots))
+		higherIsBetter = false
+	case Plurality:
+		scores = pluralityScores(choices, ballots)
+	default:
+		return nil, fmt.Errorf("%w: unsupported method %q", directdecisions.ErrInvalidData, m)
+	}
+	return standings(choices, scores, higherIsBetter), nil
+}
+
+// ranks returns ranks of choices on the ballot by choice index, where
+// unranked choices have the rank lower than any ranked choice.
+func ranks(choices []string, ballot map[string]int) []int {
+	r := make([]int, len(choices))
+	unranked := 1
+	for _, rank := range ballot {
+		if rank >= unranked {
+			unranked = rank + 1
+		}
+	}
+	for i, c := range choices {
+		if rank, ok := ballot[c]; ok {
+			r[i] = rank
+		} else {
+			r[i] = unranked
+		}
+	}
+	return r
+}
+
+// standings orders choices by scores and assigns the same place to choices
+// with equal scores.
+func standings(choices []string, scores []float64, higherIsBetter bool) []Standing {
+	s := make([]Standing, len(choices))
+	for i, c := range choices {
+		s[i] = Standing{Choice: c, Index: i, Score: scores[i]}
+	}
+	better := func(a, b float64) bool {
+		if higherIsBetter {
+			return a > b
+		}
+		return a < b
+	}
+	sort.SliceStable(s, func(i, j int) bool {
+		return better(s[i].Score, s[j].Score)
+	})
+	for i := range s {
+		if i > 0 && s[i].Score == s[i-1].Score {
+			s[i].Place = s[i-1].Place
+		} else {
+			s[i].Place = i + 1
+		}
+	}
+	return s
+}
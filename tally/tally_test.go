@@ -0,0 +1,222 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tally_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"directdecisions.com/directdecisions"
+	"directdecisions.com/directdecisions/tally"
+)
+
+// tennesseeChoices and tennesseeBallots are the example from
+// https://en.wikipedia.org/wiki/Schulze_method with 100 voters.
+var tennesseeChoices = []string{"Memphis", "Nashville", "Chattanooga", "Knoxville"}
+
+func tennesseeBallots() []map[string]int {
+	var ballots []map[string]int
+	for _, g := range []struct {
+		count int
+		order []string
+	}{
+		{42, []string{"Memphis", "Nashville", "Chattanooga", "Knoxville"}},
+		{26, []string{"Nashville", "Chattanooga", "Knoxville", "Memphis"}},
+		{15, []string{"Chattanooga", "Knoxville", "Nashville", "Memphis"}},
+		{17, []string{"Knoxville", "Chattanooga", "Nashville", "Memphis"}},
+	} {
+		for i := 0; i < g.count; i++ {
+			ballot := make(map[string]int)
+			for rank, c := range g.order {
+				ballot[c] = rank + 1
+			}
+			ballots = append(ballots, ballot)
+		}
+	}
+	return ballots
+}
+
+func TestRank(t *testing.T) {
+	for _, tc := range []struct {
+		method tally.Method
+		want   []tally.Standing
+	}{
+		{
+			method: tally.Schulze,
+			want: []tally.Standing{
+				{Choice: "Nashville", Index: 1, Place: 1, Score: 3},
+				{Choice: "Chattanooga", Index: 2, Place: 2, Score: 2},
+				{Choice: "Knoxville", Index: 3, Place: 3, Score: 1},
+				{Choice: "Memphis", Index: 0, Place: 4, Score: 0},
+			},
+		},
+		{
+			method: tally.InstantRunoff,
+			want: []tally.Standing{
+				{Choice: "Knoxville", Index: 3, Place: 1, Score: 58},
+				{Choice: "Memphis", Index: 0, Place: 2, Score: 42},
+				{Choice: "Nashville", Index: 1, Place: 3, Score: 26},
+				{Choice: "Chattanooga", Index: 2, Place: 4, Score: 15},
+			},
+		},
+		{
+			method: tally.Borda,
+			want: []tally.Standing{
+				{Choice: "Nashville", Index: 1, Place: 1, Score: 194},
+				{Choice: "Chattanooga", Index: 2, Place: 2, Score: 173},
+				{Choice: "Memphis", Index: 0, Place: 3, Score: 126},
+				{Choice: "Knoxville", Index: 3, Place: 4, Score: 107},
+			},
+		},
+		{
+			method: tally.Copeland,
+			want: []tally.Standing{
+				{Choice: "Nashville", Index: 1, Place: 1, Score: 3},
+				{Choice: "Chattanooga", Index: 2, Place: 2, Score: 2},
+				{Choice: "Knoxville", Index: 3, Place: 3, Score: 1},
+				{Choice: "Memphis", Index: 0, Place: 4, Score: 0},
+			},
+		},
+		{
+			method: tally.RankedPairs,
+			want: []tally.Standing{
+				{Choice: "Nashville", Index: 1, Place: 1, Score: 3},
+				{Choice: "Chattanooga", Index: 2, Place: 2, Score: 2},
+				{Choice: "Knoxville", Index: 3, Place: 3, Score: 1},
+				{Choice: "Memphis", Index: 0, Place: 4, Score: 0},
+			},
+		},
+		{
+			method: tally.Minimax,
+			want: []tally.Standing{
+				{Choice: "Nashville", Index: 1, Place: 1, Score: 42},
+				{Choice: "Memphis", Index: 0, Place: 2, Score: 58},
+				{Choice: "Chattanooga", Index: 2, Place: 3, Score: 68},
+				{Choice: "Knoxville", Index: 3, Place: 4, Score: 83},
+			},
+		},
+		{
+			method: tally.Plurality,
+			want: []tally.Standing{
+				{Choice: "Memphis", Index: 0, Place: 1, Score: 42},
+				{Choice: "Nashville", Index: 1, Place: 2, Score: 26},
+				{Choice: "Knoxville", Index: 3, Place: 3, Score: 17},
+				{Choice: "Chattanooga", Index: 2, Place: 4, Score: 15},
+			},
+		},
+	} {
+		t.Run(string(tc.method), func(t *testing.T) {
+			got, err := tally.Rank(tc.method, tennesseeChoices, tennesseeBallots())
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertEqual(t, "standings", got, tc.want)
+		})
+	}
+}
+
+func TestRank_tiesAndUnranked(t *testing.T) {
+	choices := []string{"Margarita", "Pepperoni", "Capricciosa"}
+	ballots := []map[string]int{
+		{"Margarita": 1, "Pepperoni": 1},
+		{"Capricciosa": 1},
+		{},
+	}
+
+	for _, tc := range []struct {
+		method tally.Method
+		want   []tally.Standing
+	}{
+		{
+			method: tally.InstantRunoff,
+			want: []tally.Standing{
+				{Choice: "Capricciosa", Index: 2, Place: 1, Score: 1},
+				{Choice: "Margarita", Index: 0, Place: 2, Score: 0.5},
+				{Choice: "Pepperoni", Index: 1, Place: 2, Score: 0.5},
+			},
+		},
+		{
+			method: tally.Borda,
+			want: []tally.Standing{
+				{Choice: "Margarita", Index: 0, Place: 1, Score: 3},
+				{Choice: "Pepperoni", Index: 1, Place: 1, Score: 3},
+				{Choice: "Capricciosa", Index: 2, Place: 1, Score: 3},
+			},
+		},
+		{
+			method: tally.Plurality,
+			want: []tally.Standing{
+				{Choice: "Capricciosa", Index: 2, Place: 1, Score: 1},
+				{Choice: "Margarita", Index: 0, Place: 2, Score: 0.5},
+				{Choice: "Pepperoni", Index: 1, Place: 2, Score: 0.5},
+			},
+		},
+		{
+			method: tally.Minimax,
+			want: []tally.Standing{
+				{Choice: "Margarita", Index: 0, Place: 1, Score: 1},
+				{Choice: "Pepperoni", Index: 1, Place: 1, Score: 1},
+				{Choice: "Capricciosa", Index: 2, Place: 1, Score: 1},
+			},
+		},
+	} {
+		t.Run(string(tc.method), func(t *testing.T) {
+			got, err := tally.Rank(tc.method, choices, ballots)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertEqual(t, "standings", got, tc.want)
+		})
+	}
+}
+
+func TestRank_invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		method  tally.Method
+		choices []string
+		ballots []map[string]int
+	}{
+		{
+			name:    "unsupported method",
+			method:  "approval",
+			choices: []string{"Margarita"},
+		},
+		{
+			name:    "duplicate choice",
+			method:  tally.Borda,
+			choices: []string{"Margarita", "Margarita"},
+		},
+		{
+			name:    "unknown choice",
+			method:  tally.Plurality,
+			choices: []string{"Margarita"},
+			ballots: []map[string]int{{"Hawaiian": 1}},
+		},
+		{
+			name:    "invalid rank",
+			method:  tally.InstantRunoff,
+			choices: []string{"Margarita"},
+			ballots: []map[string]int{{"Margarita": 0}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tally.Rank(tc.method, tc.choices, tc.ballots)
+			if !errors.Is(err, directdecisions.ErrInvalidData) {
+				t.Errorf("got error %v, want %v", err, directdecisions.ErrInvalidData)
+			}
+		})
+	}
+}
+
+func assertEqual(t testing.TB, name string, got, want any) {
+	t.Helper()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s: got %+v, want %+v", name, got, want)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Tracer receives notifications about the start and the end of every API
+// operation performed by the Client, such as a single VotingsService method
+// call, so that they can be recorded as spans by any tracing library.
+type Tracer interface {
+	// StartSpan is called before the operation starts. The returned context
+	// is used for all requests of the operation, and its TraceContext, if
+	// any, is propagated to the API.
+	StartSpan(ctx context.Context, s *Span) context.Context
+	// EndSpan is called with the context returned by StartSpan and the same
+	// Span when the operation ends, with all Span fields set.
+	EndSpan(ctx context.Context, s *Span)
+}
+
+// TraceOptions configures the content of Spans that the Client passes to its
+// Tracer.
+type TraceOptions struct {
+	// ShowVoterIDs disables redaction of voter IDs in Span paths such as
+	// v1/votings/{id}/ballots/{voterID}.
+	ShowVoterIDs bool
+}
+
+// Span holds information about an API operation.
+type Span struct {
+	Operation  string        // Name of the API operation, such as "Votings.Vote".
+	VotingID   string        // ID of the voting, if the operation is related to one.
+	Method     string        // HTTP request method.
+	Path       string        // HTTP request path relative to the base URL, with the voter ID redacted.
+	Start      time.Time     // Time when the operation started.
+	Duration   time.Duration // Duration of the operation, set when it ends.
+	StatusCode int           // HTTP status code of the last response, if it is received.
+	Attempts   int           // Number of sent HTTP requests, set when it ends.
+	Cached     bool          // The response is served from the cache without sending a request.
+	Err        error         // Error returned by the operation, set when it ends.
+}
+
+// TraceContext holds the W3C Trace Context header values that are propagated
+// to the API.
+type TraceContext struct {
+	TraceParent string // Value of the traceparent header.
+	TraceState  string // Value of the tracestate header.
+}
+
+type traceContextKey struct{}
+
+// ContextWithTraceContext returns a new context with the TraceContext that the
+// Client propagates to the API with every request made with the context.
+func ContextWithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, tc)
+}
+
+// TraceContextFromContext returns the TraceContext set by the
+// ContextWithTraceContext function.
+func TraceContextFromContext(ctx context.Context) (tc TraceContext, ok bool) {
+	tc, ok = ctx.Value(traceContextKey{}).(TraceContext)
+	return tc, ok
+}
+
+// setTraceHeaders sets W3C Trace Context headers to the request from its
+// context, if the traceparent value is valid.
+func setTraceHeaders(r *http.Request) {
+	tc, ok := TraceContextFromContext(r.Context())
+	if !ok || !validTraceParent(tc.TraceParent) {
+		return
+	}
+	r.Header.Set("traceparent", tc.TraceParent)
+	if tc.TraceState != "" {
+		r.Header.Set("tracestate", tc.TraceState)
+	}
+}
+
+// validTraceParent checks the format of the traceparent header value which is
+// version, trace ID, parent ID and trace flags as lowercase hex numbers of 2,
+// 32, 16 and 2 digits, separated by dashes.
+func validTraceParent(s string) bool {
+	parts := strings.Split(s, "-")
+	if len(parts) < 4 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return false
+	}
+	for i, l := range []int{2, 32, 16, 2} {
+		if len(parts[i]) != l || strings.Trim(parts[i], "0123456789abcdef") != "" {
+			return false
+		}
+	}
+	return strings.Trim(parts[1], "0") != "" && strings.Trim(parts[2], "0") != ""
+}
+
+// startSpan notifies the Client's tracer about the start of the operation and
+// returns the context to use for it and the started Span.
+func (c *Client) startSpan(ctx context.Context, op, method, path string, start time.Time) (context.Context, *Span) {
+	if c.tracer == nil {
+		return ctx, nil
+	}
+	if !c.traceOptions.ShowVoterIDs {
+		path = redactVoterID(path)
+	}
+	s := &Span{
+		Operation: op,
+		VotingID:  votingIDFromPath(path),
+		Method:    method,
+		Path:      path,
+		Start:     start,
+	}
+	return c.tracer.StartSpan(ctx, s), s
+}
+
+// endSpan notifies the Client's tracer about the end of the operation.
+func (c *Client) endSpan(ctx context.Context, s *Span, call *Call, err error) {
+	if s == nil {
+		return
+	}
+	s.Duration = time.Since(s.Start)
+	if call != nil {
+		s.Attempts = call.sent
+		s.StatusCode = callStatus(call, err)
+		s.Cached = call.cacheHit
+	}
+	s.Err = err
+	c.tracer.EndSpan(ctx, s)
+}
+
+// votingIDFromPath returns the voting ID from paths like v1/votings/{id}/...
+func votingIDFromPath(path string) string {
+	parts := strings.SplitN(path, "/", 4)
+	if len(parts) < 3 || parts[0] != "v1" || parts[1] != "votings" {
+		return ""
+	}
+	id, err := url.PathUnescape(parts[2])
+	if err != nil {
+		return ""
+	}
+	return id
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"directdecisions.com/directdecisions"
+)
+
+const (
+	parentTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	childTraceParent  = "00-4bf92f3577b34da6a3ce929d0e0e4736-b7ad6b7169203331-01"
+)
+
+// recordingTracer records ended spans and propagates a child span trace
+// context.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []directdecisions.Span
+}
+
+func (t *recordingTracer) StartSpan(ctx context.Context, s *directdecisions.Span) context.Context {
+	if _, ok := directdecisions.TraceContextFromContext(ctx); !ok {
+		return ctx
+	}
+	return directdecisions.ContextWithTraceContext(ctx, directdecisions.TraceContext{
+		TraceParent: childTraceParent,
+		TraceState:  "vendor=value",
+	})
+}
+
+func (t *recordingTracer) EndSpan(ctx context.Context, s *directdecisions.Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.spans = append(t.spans, *s)
+}
+
+func TestTracer(t *testing.T) {
+	tracer := new(recordingTracer)
+
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Tracer: tracer,
+	})
+
+	var traceParent, traceState string
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172/ballots/leonardo", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		traceParent = r.Header.Get("traceparent")
+		traceState = r.Header.Get("tracestate")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	ctx := directdecisions.ContextWithTraceContext(context.Background(), directdecisions.TraceContext{
+		TraceParent: parentTraceParent,
+	})
+
+	_, err := client.Votings.Ballot(ctx, "40f80454800b2bd7c172", "leonardo")
+	assertErrors(t, err, directdecisions.ErrHTTPStatusNotFound)
+
+	assertEqual(t, "traceparent", traceParent, childTraceParent)
+	assertEqual(t, "tracestate", traceState, "vendor=value")
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("got %v spans, want 1", len(tracer.spans))
+	}
+	s := tracer.spans[0]
+	assertEqual(t, "operation", s.Operation, "Votings.Ballot")
+	assertEqual(t, "voting id", s.VotingID, "40f80454800b2bd7c172")
+	assertEqual(t, "method", s.Method, http.MethodGet)
+	assertEqual(t, "path", s.Path, "v1/votings/40f80454800b2bd7c172/ballots/REDACTED")
+	assertEqual(t, "status code", s.StatusCode, http.StatusNotFound)
+	assertEqual(t, "attempts", s.Attempts, 1)
+	assertErrors(t, s.Err, directdecisions.ErrHTTPStatusNotFound)
+	if s.Start.IsZero() || s.Duration <= 0 {
+		t.Errorf("got start %s and duration %s", s.Start, s.Duration)
+	}
+}
+
+func TestTraceContext(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		traceParent string
+		want        string
+	}{
+		{
+			name:        "valid",
+			traceParent: parentTraceParent,
+			want:        parentTraceParent,
+		},
+		{
+			name:        "future version",
+			traceParent: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			want:        "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		},
+		{
+			name:        "invalid version",
+			traceParent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		{
+			name:        "zero trace id",
+			traceParent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		},
+		{
+			name:        "uppercase",
+			traceParent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00F067AA0BA902B7-01",
+		},
+		{
+			name:        "short",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-01",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client, mux, _ := newClient(t, "")
+
+			var got string
+			mux.HandleFunc("/v1/votings/40f80454800b2bd7c172", requireMethod("DELETE", func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("traceparent")
+			}))
+
+			ctx := directdecisions.ContextWithTraceContext(context.Background(), directdecisions.TraceContext{
+				TraceParent: tc.traceParent,
+			})
+
+			assertErrors(t, client.Votings.Delete(ctx, "40f80454800b2bd7c172"), nil)
+			assertEqual(t, "traceparent", got, tc.want)
+		})
+	}
+}
+
+func TestTracer_showVoterIDs(t *testing.T) {
+	tracer := new(recordingTracer)
+
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Tracer:       tracer,
+		TraceOptions: &directdecisions.TraceOptions{ShowVoterIDs: true},
+	})
+
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172/ballots/leonardo", requireMethod("DELETE", func(w http.ResponseWriter, r *http.Request) {}))
+
+	assertErrors(t, client.Votings.Unvote(context.Background(), "40f80454800b2bd7c172", "leonardo"), nil)
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("got %v spans, want 1", len(tracer.spans))
+	}
+	assertEqual(t, "path", tracer.spans[0].Path, "v1/votings/40f80454800b2bd7c172/ballots/leonardo")
+}
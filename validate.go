@@ -0,0 +1,149 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limits holds the API limits that arguments of VotingsService methods are
+// validated against before any request is sent. Validation errors are the
+// same ones that the API returns.
+type Limits struct {
+	// MaxChoices is the maximal number of choices in a voting. If it is
+	// zero, 100 is used.
+	MaxChoices int
+	// MaxChoiceLength is the maximal length of a choice in bytes. If it is
+	// zero, 256 is used.
+	MaxChoiceLength int
+	// MaxVoterIDLength is the maximal length of a voter ID in bytes. If it
+	// is zero, 256 is used.
+	MaxVoterIDLength int
+}
+
+const (
+	defaultMaxChoices       = 100
+	defaultMaxChoiceLength  = 256
+	defaultMaxVoterIDLength = 256
+)
+
+// validator checks arguments against the configured limits. A nil validator
+// accepts all arguments.
+type validator struct {
+	maxChoices       int
+	maxChoiceLength  int
+	maxVoterIDLength int
+}
+
+func newValidator(l *Limits) *validator {
+	if l == nil {
+		return nil
+	}
+	v := &validator{
+		maxChoices:       l.MaxChoices,
+		maxChoiceLength:  l.MaxChoiceLength,
+		maxVoterIDLength: l.MaxVoterIDLength,
+	}
+	if v.maxChoices <= 0 {
+		v.maxChoices = defaultMaxChoices
+	}
+	if v.maxChoiceLength <= 0 {
+		v.maxChoiceLength = defaultMaxChoiceLength
+	}
+	if v.maxVoterIDLength <= 0 {
+		v.maxVoterIDLength = defaultMaxVoterIDLength
+	}
+	return v
+}
+
+// choices validates choices of a new voting.
+func (v *validator) choices(choices []string) error {
+	if v == nil {
+		return nil
+	}
+	if len(choices) == 0 {
+		return ErrMissingChoices
+	}
+	if len(choices) > v.maxChoices {
+		return ErrTooManyChoices
+	}
+	seen := make(map[string]struct{}, len(choices))
+	for _, c := range choices {
+		if err := v.choice(c); err != nil {
+			return err
+		}
+		if _, ok := seen[c]; ok {
+			return fmt.Errorf("%w: duplicate choice %q", ErrInvalidData, c)
+		}
+		seen[c] = struct{}{}
+	}
+	return nil
+}
+
+func (v *validator) choice(c string) error {
+	if v == nil {
+		return nil
+	}
+	if strings.TrimSpace(c) == "" {
+		return ErrChoiceRequired
+	}
+	if len(c) > v.maxChoiceLength {
+		return ErrChoiceTooLong
+	}
+	return nil
+}
+
+// voterID validates the voter ID which must be valid UTF-8 and must not
+// contain whitespace or control characters.
+func (v *validator) voterID(id string) error {
+	if v == nil {
+		return nil
+	}
+	if len(id) > v.maxVoterIDLength {
+		return ErrVoterIDTooLong
+	}
+	if id == "" || !utf8.ValidString(id) || strings.IndexFunc(id, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r)
+	}) >= 0 {
+		return ErrInvalidVoterID
+	}
+	return nil
+}
+
+// vote validates the voter ID and the ballot, returning all errors like the
+// API does.
+func (v *validator) vote(voterID string, ballot map[string]int) error {
+	if v == nil {
+		return nil
+	}
+	var errs []error
+	if err := v.voterID(voterID); err != nil {
+		errs = append(errs, err)
+	}
+	if len(ballot) == 0 {
+		errs = append(errs, ErrBallotRequired)
+	}
+	return errors.Join(errs...)
+}
+
+// reject reports the operation with arguments that are rejected by the
+// validator to the Client's logger, metrics and tracer, as if it has failed
+// without sending a request, and returns the validation error.
+func (c *Client) reject(ctx context.Context, op, method, path string, err error) error {
+	start := time.Now()
+	ctx, span := c.startSpan(ctx, op, method, path, start)
+	d := time.Since(start)
+	c.logger.log(ctx, op, method, path, nil, nil, d, err)
+	c.observe(op, nil, d, err)
+	c.endSpan(ctx, span, nil, err)
+	return err
+}
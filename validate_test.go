@@ -0,0 +1,172 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"directdecisions.com/directdecisions"
+)
+
+func TestLimits(t *testing.T) {
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Limits: &directdecisions.Limits{
+			MaxChoices:       3,
+			MaxChoiceLength:  10,
+			MaxVoterIDLength: 8,
+		},
+	})
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+
+	ctx := context.Background()
+	const votingID = "40f80454800b2bd7c172"
+
+	for _, tc := range []struct {
+		name string
+		call func() error
+		errs []error
+	}{
+		{
+			name: "create without choices",
+			call: func() error {
+				_, err := client.Votings.Create(ctx, nil)
+				return err
+			},
+			errs: []error{directdecisions.ErrMissingChoices},
+		},
+		{
+			name: "create with too many choices",
+			call: func() error {
+				_, err := client.Votings.Create(ctx, []string{"Margarita", "Pepperoni", "Capricciosa", "Hawaiian"})
+				return err
+			},
+			errs: []error{directdecisions.ErrTooManyChoices},
+		},
+		{
+			name: "create with empty choice",
+			call: func() error {
+				_, err := client.Votings.Create(ctx, []string{"Margarita", " "})
+				return err
+			},
+			errs: []error{directdecisions.ErrChoiceRequired},
+		},
+		{
+			name: "create with too long choice",
+			call: func() error {
+				_, err := client.Votings.Create(ctx, []string{"Quattro Formaggi"})
+				return err
+			},
+			errs: []error{directdecisions.ErrChoiceTooLong},
+		},
+		{
+			name: "create with duplicate choices",
+			call: func() error {
+				_, err := client.Votings.Create(ctx, []string{"Margarita", "Pepperoni", "Margarita"})
+				return err
+			},
+			errs: []error{directdecisions.ErrInvalidData},
+		},
+		{
+			name: "set too long choice",
+			call: func() error {
+				_, err := client.Votings.Set(ctx, votingID, "Quattro Formaggi", 0)
+				return err
+			},
+			errs: []error{directdecisions.ErrChoiceTooLong},
+		},
+		{
+			name: "vote with empty ballot and invalid voter id",
+			call: func() error {
+				_, err := client.Votings.Vote(ctx, votingID, "leo nard", nil)
+				return err
+			},
+			errs: []error{directdecisions.ErrInvalidVoterID, directdecisions.ErrBallotRequired},
+		},
+		{
+			name: "ballot with too long voter id",
+			call: func() error {
+				_, err := client.Votings.Ballot(ctx, votingID, strings.Repeat("l", 9))
+				return err
+			},
+			errs: []error{directdecisions.ErrVoterIDTooLong},
+		},
+		{
+			name: "unvote with empty voter id",
+			call: func() error {
+				return client.Votings.Unvote(ctx, votingID, "")
+			},
+			errs: []error{directdecisions.ErrInvalidVoterID},
+		},
+		{
+			name: "unvote with invalid utf-8 voter id",
+			call: func() error {
+				return client.Votings.Unvote(ctx, votingID, "leo\xff")
+			},
+			errs: []error{directdecisions.ErrInvalidVoterID},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assertErrors(t, tc.call(), tc.errs...)
+		})
+	}
+}
+
+func TestLimits_valid(t *testing.T) {
+	client, mux, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Limits: new(directdecisions.Limits),
+	})
+
+	mux.HandleFunc("/v1/votings", requireMethod("POST", newStaticHandler(`{"id":"40f80454800b2bd7c172","choices":["Margarita","Pepperoni"]}`)))
+
+	v, err := client.Votings.Create(context.Background(), []string{"Margarita", "Pepperoni"})
+	assertErrors(t, err, nil)
+	assertEqual(t, "id", v.ID, "40f80454800b2bd7c172")
+}
+
+func TestLimits_observed(t *testing.T) {
+	var buf bytes.Buffer
+	metrics := directdecisions.NewPrometheusMetrics(nil)
+	tracer := new(recordingTracer)
+	client, _, _ := newClientWithOptions(t, "", &directdecisions.ClientOptions{
+		Limits:  &directdecisions.Limits{MaxChoiceLength: 5},
+		Logger:  slog.New(slog.NewJSONHandler(&buf, nil)),
+		Metrics: metrics,
+		Tracer:  tracer,
+	})
+
+	_, err := client.Votings.Create(context.Background(), []string{"Margarita"})
+	assertErrors(t, err, directdecisions.ErrChoiceTooLong)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, "operation", record["operation"], "Votings.Create")
+	assertEqual(t, "error class", record["error_class"], "invalid_request")
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("got %v spans, want 1", len(tracer.spans))
+	}
+	assertEqual(t, "span operation", tracer.spans[0].Operation, "Votings.Create")
+	assertErrors(t, tracer.spans[0].Err, directdecisions.ErrChoiceTooLong)
+
+	var out strings.Builder
+	if _, err := metrics.WriteTo(&out); err != nil {
+		t.Fatal(err)
+	}
+	if want := `directdecisions_errors_total{operation="Votings.Create",error="ErrChoiceTooLong"} 1`; !strings.Contains(out.String(), want) {
+		t.Errorf("metrics do not contain %s:\n%s", want, out.String())
+	}
+}
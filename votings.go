@@ -22,18 +22,21 @@ type Voting struct {
 
 // Voting returns a specific voting referenced by its ID.
 func (s *VotingsService) Voting(ctx context.Context, votingID string) (v *Voting, err error) {
-	err = s.client.request(ctx, http.MethodGet, "v1/votings/"+url.PathEscape(votingID), nil, &v)
+	err = s.client.request(ctx, "Votings.Voting", http.MethodGet, "v1/votings/"+url.PathEscape(votingID), nil, &v)
 	return v, err
 }
 
 // Create adds a new voting with a provided choices.
 func (s *VotingsService) Create(ctx context.Context, choices []string) (v *Voting, err error) {
+	if err := s.client.validator.choices(choices); err != nil {
+		return nil, s.client.reject(ctx, "Votings.Create", http.MethodPost, "v1/votings", err)
+	}
 
 	type createVotingRequest struct {
 		Choices []string `json:"choices"`
 	}
 
-	err = s.client.request(ctx, http.MethodPost, "v1/votings", createVotingRequest{
+	err = s.client.request(ctx, "Votings.Create", http.MethodPost, "v1/votings", createVotingRequest{
 		Choices: choices,
 	}, &v)
 	return v, err
@@ -41,6 +44,10 @@ func (s *VotingsService) Create(ctx context.Context, choices []string) (v *Votin
 
 // Set adds, moves or removes a choice in a voting.
 func (s *VotingsService) Set(ctx context.Context, votingID, choice string, index int) (choices []string, err error) {
+	path := "v1/votings/" + url.PathEscape(votingID) + "/choices"
+	if err := s.client.validator.choice(choice); err != nil {
+		return nil, s.client.reject(ctx, "Votings.Set", http.MethodPost, path, err)
+	}
 
 	type setChoiceRequest struct {
 		Choice string `json:"choice"`
@@ -52,7 +59,7 @@ func (s *VotingsService) Set(ctx context.Context, votingID, choice string, index
 	}
 
 	var response *setChoiceResponse
-	if err = s.client.request(ctx, http.MethodPost, "v1/votings/"+url.PathEscape(votingID)+"/choices", setChoiceRequest{
+	if err = s.client.request(ctx, "Votings.Set", http.MethodPost, path, setChoiceRequest{
 		Choice: choice,
 		Index:  index,
 	}, &response); err != nil {
@@ -63,23 +70,31 @@ func (s *VotingsService) Set(ctx context.Context, votingID, choice string, index
 
 // Delete removes a voting referenced by its ID.
 func (s *VotingsService) Delete(ctx context.Context, votingID string) (err error) {
-	return s.client.request(ctx, http.MethodDelete, "v1/votings/"+url.PathEscape(votingID), nil, nil)
+	return s.client.request(ctx, "Votings.Delete", http.MethodDelete, "v1/votings/"+url.PathEscape(votingID), nil, nil)
 }
 
 func (s *VotingsService) Ballot(ctx context.Context, votingID, voterID string) (ballot map[string]int, err error) {
+	path := "v1/votings/" + url.PathEscape(votingID) + "/ballots/" + url.PathEscape(voterID)
+	if err := s.client.validator.voterID(voterID); err != nil {
+		return nil, s.client.reject(ctx, "Votings.Ballot", http.MethodGet, path, err)
+	}
 
 	type ballotResponse struct {
 		Ballot map[string]int `json:"ballot"`
 	}
 
 	var response *ballotResponse
-	if err = s.client.request(ctx, http.MethodGet, "v1/votings/"+url.PathEscape(votingID)+"/ballots/"+url.PathEscape(voterID), nil, &response); err != nil {
+	if err = s.client.request(ctx, "Votings.Ballot", http.MethodGet, path, nil, &response); err != nil {
 		return nil, err
 	}
 	return response.Ballot, nil
 }
 
 func (s *VotingsService) Vote(ctx context.Context, votingID, voterID string, ballot map[string]int) (revoted bool, err error) {
+	path := "v1/votings/" + url.PathEscape(votingID) + "/ballots/" + url.PathEscape(voterID)
+	if err := s.client.validator.vote(voterID, ballot); err != nil {
+		return false, s.client.reject(ctx, "Votings.Vote", http.MethodPost, path, err)
+	}
 
 	type voteRequest struct {
 		Ballot map[string]int `json:"ballot"`
@@ -90,7 +105,7 @@ func (s *VotingsService) Vote(ctx context.Context, votingID, voterID string, bal
 	}
 
 	var response *voteResponse
-	if err = s.client.request(ctx, http.MethodPost, "v1/votings/"+url.PathEscape(votingID)+"/ballots/"+url.PathEscape(voterID), voteRequest{
+	if err = s.client.request(ctx, "Votings.Vote", http.MethodPost, path, voteRequest{
 		Ballot: ballot,
 	}, &response); err != nil {
 		return false, err
@@ -99,16 +114,21 @@ func (s *VotingsService) Vote(ctx context.Context, votingID, voterID string, bal
 }
 
 func (s *VotingsService) Unvote(ctx context.Context, votingID, voterID string) error {
-	return s.client.request(ctx, http.MethodDelete, "v1/votings/"+url.PathEscape(votingID)+"/ballots/"+url.PathEscape(voterID), nil, nil)
+	path := "v1/votings/" + url.PathEscape(votingID) + "/ballots/" + url.PathEscape(voterID)
+	if err := s.client.validator.voterID(voterID); err != nil {
+		return s.client.reject(ctx, "Votings.Unvote", http.MethodDelete, path, err)
+	}
+	return s.client.request(ctx, "Votings.Unvote", http.MethodDelete, path, nil, nil)
 }
 
+// Result holds the position of a choice in voting results.
 type Result struct {
-	Choice     string
-	Index      int
-	Wins       int
-	Percentage float64
-	Strength   int
-	Advantage  int
+	Choice     string  `json:"choice"`
+	Index      int     `json:"index"`
+	Wins       int     `json:"wins"`
+	Percentage float64 `json:"percentage"`
+	Strength   int     `json:"strength"`
+	Advantage  int     `json:"advantage"`
 }
 
 type computeResultsAPIResponse struct {
@@ -120,28 +140,31 @@ type computeResultsAPIResponse struct {
 func (s *VotingsService) Results(ctx context.Context, votingID string) (results []Result, tie bool, err error) {
 
 	var response *computeResultsAPIResponse
-	if err = s.client.request(ctx, http.MethodGet, "v1/votings/"+url.PathEscape(votingID)+"/results", nil, &response); err != nil {
+	if err = s.client.request(ctx, "Votings.Results", http.MethodGet, "v1/votings/"+url.PathEscape(votingID)+"/results", nil, &response); err != nil {
 		return nil, false, err
 	}
 
 	return response.Results, response.Tie, nil
 }
 
+// Duel holds the pairwise comparison of two choices.
 type Duel struct {
-	Left  ChoiceStrength
-	Right ChoiceStrength
+	Left  ChoiceStrength `json:"left"`
+	Right ChoiceStrength `json:"right"`
 }
 
+// ChoiceStrength holds the number of voters that prefer the choice over the
+// other one in a Duel.
 type ChoiceStrength struct {
-	Choice   string
-	Index    int
-	Strength int
+	Choice   string `json:"choice"`
+	Index    int    `json:"index"`
+	Strength int    `json:"strength"`
 }
 
 func (s *VotingsService) Duels(ctx context.Context, votingID string) (results []Result, duels []Duel, tie bool, err error) {
 
 	var response *computeResultsAPIResponse
-	if err = s.client.request(ctx, http.MethodGet, "v1/votings/"+url.PathEscape(votingID)+"/results/duels", nil, &response); err != nil {
+	if err = s.client.request(ctx, "Votings.Duels", http.MethodGet, "v1/votings/"+url.PathEscape(votingID)+"/results/duels", nil, &response); err != nil {
 		return nil, nil, false, err
 	}
 
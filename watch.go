@@ -0,0 +1,204 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+const defaultWatchInterval = 5 * time.Second
+
+// EventType is the type of the change reported by the results watcher.
+type EventType string
+
+// Types of watcher events.
+const (
+	// EventResults is sent with the first results of a voting and with
+	// every subsequent change of results, before more specific events.
+	EventResults EventType = "results"
+	// EventLeaderChanged is sent when the choice at the first place changes.
+	EventLeaderChanged EventType = "leader-changed"
+	// EventTieAppeared is sent when the voting becomes tied.
+	EventTieAppeared EventType = "tie-appeared"
+	// EventTieResolved is sent when the voting is not tied anymore.
+	EventTieResolved EventType = "tie-resolved"
+	// EventPercentageChanged is sent for every choice whose percentage of
+	// wins changed.
+	EventPercentageChanged EventType = "percentage-changed"
+	// EventError is sent when results could not be retrieved. Watching
+	// continues with the next poll.
+	EventError EventType = "error"
+)
+
+// WatchOptions holds optional parameters for watching results.
+type WatchOptions struct {
+	// Interval is the minimal duration between polls of all watched votings.
+	// It is extended to spread the remaining rate limit over the current
+	// rate limit window. If it is zero, 5 seconds is used.
+	Interval time.Duration
+	// Duels enables retrieving of duels together with results.
+	Duels bool
+}
+
+// Snapshot holds results of a voting at a point in time.
+type Snapshot struct {
+	Results []Result
+	Duels   []Duel // Only if WatchOptions Duels is set.
+	Tie     bool
+}
+
+// Event is a change of voting results.
+type Event struct {
+	Type     EventType
+	VotingID string
+	Time     time.Time // Time when results were retrieved.
+	Snapshot *Snapshot // Current results, nil for EventError.
+	Previous *Snapshot // Previous results, nil for the first EventResults.
+	Choice   string    // Choice with changed percentage for EventPercentageChanged.
+	Err      error     // Error for EventError.
+}
+
+// Watch polls results of votings and sends events about their changes on the
+// returned channel, which is closed when the context is done. Unchanged
+// results do not produce events.
+func (s *VotingsService) Watch(ctx context.Context, votingIDs []string, o *WatchOptions) <-chan Event {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		_ = s.WatchFunc(ctx, votingIDs, o, func(e Event) {
+			select {
+			case events <- e:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return events
+}
+
+// WatchFunc polls results of votings and calls the function for every event
+// about their changes until the context is done, when it returns the context
+// error. The function is called sequentially from the calling goroutine.
+func (s *VotingsService) WatchFunc(ctx context.Context, votingIDs []string, o *WatchOptions, f func(Event)) error {
+	interval := defaultWatchInterval
+	var duels bool
+	if o != nil {
+		if o.Interval > 0 {
+			interval = o.Interval
+		}
+		duels = o.Duels
+	}
+
+	previous := make(map[string]*Snapshot, len(votingIDs))
+	for {
+		for _, id := range votingIDs {
+			snapshot, err := s.snapshot(ctx, id, duels)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			now := time.Now()
+			if err != nil {
+				f(Event{Type: EventError, VotingID: id, Time: now, Err: err})
+				continue
+			}
+			for _, e := range snapshotEvents(previous[id], snapshot) {
+				e.VotingID = id
+				e.Time = now
+				f(e)
+			}
+			previous[id] = snapshot
+		}
+
+		d := pollInterval(s.client.Rate(), time.Now(), interval, len(votingIDs))
+		if err := sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *VotingsService) snapshot(ctx context.Context, votingID string, duels bool) (*Snapshot, error) {
+	var (
+		snapshot Snapshot
+		err      error
+	)
+	if duels {
+		snapshot.Results, snapshot.Duels, snapshot.Tie, err = s.Duels(ctx, votingID)
+	} else {
+		snapshot.Results, snapshot.Tie, err = s.Results(ctx, votingID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// snapshotEvents returns events about changes between two snapshots.
+func snapshotEvents(previous, current *Snapshot) (events []Event) {
+	if previous == nil {
+		return []Event{{Type: EventResults, Snapshot: current}}
+	}
+	if reflect.DeepEqual(previous, current) {
+		return nil
+	}
+	event := func(t EventType) Event {
+		return Event{Type: t, Snapshot: current, Previous: previous}
+	}
+
+	events = append(events, event(EventResults))
+	if leader(previous) != leader(current) {
+		events = append(events, event(EventLeaderChanged))
+	}
+	if !previous.Tie && current.Tie {
+		events = append(events, event(EventTieAppeared))
+	}
+	if previous.Tie && !current.Tie {
+		events = append(events, event(EventTieResolved))
+	}
+	percentages := make(map[string]float64, len(previous.Results))
+	for _, r := range previous.Results {
+		percentages[r.Choice] = r.Percentage
+	}
+	for _, r := range current.Results {
+		if p, ok := percentages[r.Choice]; ok && p != r.Percentage {
+			e := event(EventPercentageChanged)
+			e.Choice = r.Choice
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+func leader(s *Snapshot) string {
+	if len(s.Results) == 0 {
+		return ""
+	}
+	return s.Results[0].Choice
+}
+
+// pollInterval returns the duration until the next poll of the requests
+// number of votings, so that the remaining rate limit is spread over the
+// rest of the rate limit window, but not shorter than min.
+func pollInterval(r Rate, now time.Time, min time.Duration, requests int) time.Duration {
+	d := min
+	if r.Limit > 0 && r.Reset.After(now) && requests > 0 {
+		window := r.Reset.Sub(now)
+		if polls := r.Remaining / requests; polls < 1 {
+			d = window
+		} else {
+			d = window / time.Duration(polls)
+		}
+	}
+	if r.Retry.After(now) {
+		if retry := r.Retry.Sub(now); retry > d {
+			d = retry
+		}
+	}
+	if d < min {
+		return min
+	}
+	return d
+}
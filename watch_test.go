@@ -0,0 +1,155 @@
+// Copyright (c) 2022, Direct Decisions Go client AUTHORS.
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package directdecisions_test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"directdecisions.com/directdecisions"
+)
+
+func TestVotingsService_WatchFunc(t *testing.T) {
+	client, mux, _ := newClient(t, "")
+
+	responses := []string{
+		`{"results": [{"choice": "Margarita", "index": 0, "wins": 1, "percentage": 100}, {"choice": "Pepperoni", "index": 1}], "tie": false}`,
+		`{"results": [{"choice": "Margarita", "index": 0, "wins": 1, "percentage": 100}, {"choice": "Pepperoni", "index": 1}], "tie": false}`,
+		`{"results": [{"choice": "Pepperoni", "index": 1, "wins": 1, "percentage": 100}, {"choice": "Margarita", "index": 0}], "tie": false}`,
+		`{"results": [{"choice": "Pepperoni", "index": 1}, {"choice": "Margarita", "index": 0}], "tie": true}`,
+		`{"results": [{"choice": "Pepperoni", "index": 1, "wins": 1, "percentage": 100}, {"choice": "Margarita", "index": 0}], "tie": false}`,
+	}
+	var polls atomic.Int64
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172/results", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		n := int(polls.Add(1)) - 1
+		if n >= len(responses) {
+			n = len(responses) - 1
+		}
+		newStaticHandler(responses[n])(w, r)
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type event struct {
+		typ    directdecisions.EventType
+		choice string
+		leader string
+	}
+	var got []event
+	err := client.Votings.WatchFunc(ctx, []string{"40f80454800b2bd7c172"}, &directdecisions.WatchOptions{
+		Interval: time.Millisecond,
+	}, func(e directdecisions.Event) {
+		assertEqual(t, "voting id", e.VotingID, "40f80454800b2bd7c172")
+		got = append(got, event{typ: e.Type, choice: e.Choice, leader: e.Snapshot.Results[0].Choice})
+		if polls.Load() >= int64(len(responses)) {
+			cancel()
+		}
+	})
+	assertErrors(t, err, context.Canceled)
+
+	assertEqual(t, "events", got, []event{
+		{typ: directdecisions.EventResults, leader: "Margarita"},
+		{typ: directdecisions.EventResults, leader: "Pepperoni"},
+		{typ: directdecisions.EventLeaderChanged, leader: "Pepperoni"},
+		{typ: directdecisions.EventPercentageChanged, choice: "Pepperoni", leader: "Pepperoni"},
+		{typ: directdecisions.EventPercentageChanged, choice: "Margarita", leader: "Pepperoni"},
+		{typ: directdecisions.EventResults, leader: "Pepperoni"},
+		{typ: directdecisions.EventTieAppeared, leader: "Pepperoni"},
+		{typ: directdecisions.EventPercentageChanged, choice: "Pepperoni", leader: "Pepperoni"},
+		{typ: directdecisions.EventResults, leader: "Pepperoni"},
+		{typ: directdecisions.EventTieResolved, leader: "Pepperoni"},
+		{typ: directdecisions.EventPercentageChanged, choice: "Pepperoni", leader: "Pepperoni"},
+	})
+}
+
+func TestVotingsService_Watch(t *testing.T) {
+	client, mux, _ := newClient(t, "")
+
+	mux.HandleFunc("/v1/votings/40f80454800b2bd7c172/results/duels", requireMethod("GET", newStaticHandler(`{"results": [{"choice": "Margarita", "index": 0, "wins": 1, "percentage": 100}, {"choice": "Pepperoni", "index": 1}], "duels": [{"left": {"choice": "Margarita", "index": 0, "strength": 1}, "right": {"choice": "Pepperoni", "index": 1, "strength": 0}}], "tie": false}`)))
+	mux.HandleFunc("/v1/votings/missing/results/duels", requireMethod("GET", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := client.Votings.Watch(ctx, []string{"40f80454800b2bd7c172", "missing"}, &directdecisions.WatchOptions{
+		Interval: time.Millisecond,
+		Duels:    true,
+	})
+
+	e := <-events
+	assertEqual(t, "type", e.Type, directdecisions.EventResults)
+	assertEqual(t, "voting id", e.VotingID, "40f80454800b2bd7c172")
+	assertEqual(t, "duels", len(e.Snapshot.Duels), 1)
+	if e.Previous != nil {
+		t.Errorf("got previous snapshot %+v", e.Previous)
+	}
+
+	e = <-events
+	assertEqual(t, "type", e.Type, directdecisions.EventError)
+	assertEqual(t, "voting id", e.VotingID, "missing")
+	assertErrors(t, e.Err, directdecisions.ErrHTTPStatusNotFound)
+
+	// Unchanged results do not produce events.
+	e = <-events
+	assertEqual(t, "type", e.Type, directdecisions.EventError)
+	assertEqual(t, "voting id", e.VotingID, "missing")
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestPollInterval(t *testing.T) {
+	now := time.Now()
+
+	for _, tc := range []struct {
+		name     string
+		rate     directdecisions.Rate
+		requests int
+		want     time.Duration
+	}{
+		{
+			name:     "no rate",
+			requests: 1,
+			want:     time.Second,
+		},
+		{
+			name:     "spread remaining",
+			rate:     directdecisions.Rate{Limit: 100, Remaining: 20, Reset: now.Add(time.Minute)},
+			requests: 2,
+			want:     6 * time.Second,
+		},
+		{
+			name:     "minimal interval",
+			rate:     directdecisions.Rate{Limit: 100, Remaining: 100, Reset: now.Add(time.Minute)},
+			requests: 1,
+			want:     time.Second,
+		},
+		{
+			name:     "exhausted",
+			rate:     directdecisions.Rate{Limit: 100, Remaining: 1, Reset: now.Add(time.Minute)},
+			requests: 2,
+			want:     time.Minute,
+		},
+		{
+			name:     "retry",
+			rate:     directdecisions.Rate{Retry: now.Add(30 * time.Second)},
+			requests: 1,
+			want:     30 * time.Second,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := directdecisions.PollInterval(tc.rate, now, time.Second, tc.requests)
+			assertEqual(t, "interval", got, tc.want)
+		})
+	}
+}